@@ -3,12 +3,17 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/danielss-dev/critica/internal/ai"
+	"github.com/danielss-dev/critica/internal/config"
 	"github.com/danielss-dev/critica/internal/git"
 	"github.com/danielss-dev/critica/internal/parser"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -21,11 +26,12 @@ var aiCmd = &cobra.Command{
 - Bug detection
 - Commit message generation
 - PR description generation
-- Change explanations`,
+- Change explanations
+- Inline review comments`,
 }
 
 var analyzeCmd = &cobra.Command{
-	Use:   "analyze [path]",
+	Use:   "analyze [path | commit]",
 	Short: "Perform comprehensive AI analysis of git diff",
 	Long: `Analyze the git diff with AI to get insights about:
 - Code quality assessment
@@ -33,7 +39,10 @@ var analyzeCmd = &cobra.Command{
 - Security concerns
 - Performance implications
 - Improvement suggestions
-- Explanations of changes`,
+- Explanations of changes
+
+Pass a commit (hash, branch, tag or HEAD~2) instead of a path to analyze what that
+commit changed. An argument that exists as a path is always treated as a path.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAIAnalysis,
 }
@@ -42,7 +51,13 @@ var commitCmd = &cobra.Command{
 	Use:   "commit [path]",
 	Short: "Generate conventional commit message",
 	Long: `Generate a conventional commit message based on the git diff.
-Uses AI to analyze changes and create appropriate commit messages following conventional commit format.`,
+Uses AI to analyze changes and create appropriate commit messages following conventional commit format.
+
+After the message is shown you're asked whether to commit: y applies it, n skips it and
+e opens it in your editor first. With --apply (or --yes) the staged changes are committed
+without asking and the new commit's hash is printed. Add --all to stage every change
+first, and --edit to adjust the message in your editor before it is used, as with git
+commit.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAIGenerateCommit,
 }
@@ -74,19 +89,59 @@ Provides clear explanations of what each change does and why it might have been
 	RunE: runAIExplain,
 }
 
+var summaryCmd = &cobra.Command{
+	Use:   "summary [path]",
+	Short: "Print a one-paragraph summary of the changes",
+	Long: `Summarize the git diff in a single short paragraph, for standups and quick overviews.
+Only the paragraph is printed, so the output can be piped or pasted as-is.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAISummary,
+}
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [path]",
+	Short: "Generate inline review comments",
+	Long: `Review the git diff with AI and get comments anchored to specific files and lines.
+Each comment is mapped back to a line in the diff, like review comments on a pull request.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAIReview,
+}
+
+var (
+	commitType  string
+	commitScope string
+	commitApply bool
+	commitAll   bool
+	commitEdit  bool
+)
+
 func init() {
+	commitCmd.Flags().StringVar(&commitType, "type", "", "Conventional commit type the message must use (feat, fix, docs, ...)")
+	commitCmd.Flags().StringVar(&commitScope, "scope", "", "Conventional commit scope the message must use (requires --type)")
+	commitCmd.Flags().BoolVar(&commitApply, "apply", false, "Commit the staged changes with the generated message without asking")
+	commitCmd.Flags().BoolVarP(&commitApply, "yes", "y", false, "Same as --apply, for scripts and CI")
+	commitCmd.Flags().BoolVar(&commitAll, "all", false, "Stage all changes before generating the message")
+	commitCmd.Flags().BoolVar(&commitEdit, "edit", false, "Open the generated message in your editor before committing")
+
 	rootCmd.AddCommand(aiCmd)
 	aiCmd.AddCommand(analyzeCmd)
 	aiCmd.AddCommand(commitCmd)
 	aiCmd.AddCommand(prCmd)
 	aiCmd.AddCommand(improveCmd)
 	aiCmd.AddCommand(explainCmd)
+	aiCmd.AddCommand(summaryCmd)
+	aiCmd.AddCommand(reviewCmd)
 }
 
 func runAIAnalysis(cmd *cobra.Command, args []string) error {
 	path := "."
+	commit := ""
 	if len(args) > 0 {
-		path = args[0]
+		if isCommitArg(args[0]) {
+			commit = args[0]
+		} else {
+			path = args[0]
+		}
 	}
 
 	// Check if we're in a git repository
@@ -95,7 +150,13 @@ func runAIAnalysis(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get the diff
-	diffOutput, err := git.GetDiff(path, staged)
+	var diffOutput string
+	var err error
+	if commit != "" {
+		diffOutput, err = git.GetCommitDiff(path, commit)
+	} else {
+		diffOutput, err = git.GetDiff(path, staged)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get diff: %w", err)
 	}
@@ -110,22 +171,34 @@ func runAIAnalysis(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse diff: %w", err)
 	}
+	if len(files) == 0 {
+		fmt.Println("No changes to analyze")
+		return nil
+	}
 
 	// Load AI configuration
-	aiConfig := ai.LoadConfig()
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
 	if aiConfig.APIKey == "" {
 		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
 	}
 
 	// Create AI service
 	aiService := ai.NewService(aiConfig)
+	printWithheldFiles(aiService, files)
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	fmt.Print("🤖 Analyzing changes with AI...")
-	fmt.Println()
+	if commit != "" {
+		fmt.Printf("🤖 Analyzing commit %s with AI...\n", commit)
+	} else {
+		fmt.Print("🤖 Analyzing changes with AI...")
+		fmt.Println()
+	}
 
 	// Perform analysis (quiet streaming - no visible output during processing)
 	result, err := aiService.AnalyzeDiff(ctx, files)
@@ -133,11 +206,32 @@ func runAIAnalysis(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("AI analysis failed: %w", err)
 	}
 
+	printRedactions(aiService)
+
 	// Display results
 	displayAnalysisResult(result)
 	return nil
 }
 
+// branchCommitSummary describes the commits a PR from source to target contains for the
+// prompt, or returns "" when they can't be listed; the summary is only extra context
+func branchCommitSummary(path, source, target string) string {
+	summary, err := git.GetBranchCommitSummary(path, source, target)
+	if err != nil {
+		return ""
+	}
+	return summary.Describe(time.Now())
+}
+
+// isCommitArg reports whether a command argument names a commit rather than a path. A
+// name that exists on disk is a path, even if it is also a branch or tag.
+func isCommitArg(arg string) bool {
+	if _, err := os.Stat(arg); err == nil {
+		return false
+	}
+	return git.VerifyCommit(".", arg) == nil
+}
+
 func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 	path := "."
 	if len(args) > 0 {
@@ -149,6 +243,20 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not a git repository: %s", path)
 	}
 
+	commitOpts, err := commitOptionsFromFlags()
+	if err != nil {
+		return err
+	}
+
+	if commitAll {
+		if appConfig.NeverAddAll() {
+			return fmt.Errorf("--all stages every change, but commit_never_add_all is enabled")
+		}
+		if err := git.StageAllFiles(path); err != nil {
+			return fmt.Errorf("failed to stage files: %w", err)
+		}
+	}
+
 	// Check if there are staged changes
 	hasStaged, err := git.HasStagedChanges(path)
 	if err != nil {
@@ -174,13 +282,25 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
+		// --apply never commits more than the user chose to
+		if commitApply {
+			return fmt.Errorf("no staged changes to commit: stage files with 'git add' or pass --all")
+		}
+
+		if appConfig.NeverAddAll() {
+			fmt.Println("No staged changes. Stage the files you want to commit with 'git add' first (commit_never_add_all is enabled).")
+			return nil
+		}
+
 		// Stage all files automatically
 		fmt.Println("No staged changes found. Staging all modified files...")
 		err = git.StageAllFiles(path)
 		if err != nil {
 			return fmt.Errorf("failed to stage files: %w", err)
 		}
-		fmt.Println("✅ Files staged successfully!")
+		if !dryRun {
+			fmt.Println("✅ Files staged successfully!")
+		}
 
 		// Get the staged diff after staging
 		diffOutput, err = git.GetDiff(path, staged)
@@ -199,15 +319,30 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse diff: %w", err)
 	}
+	if len(files) == 0 {
+		fmt.Println("No changes to commit")
+		return nil
+	}
 
 	// Load AI configuration
-	aiConfig := ai.LoadConfig()
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
 	if aiConfig.APIKey == "" {
 		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
 	}
 
 	// Create AI service
 	aiService := ai.NewService(aiConfig)
+	printWithheldFiles(aiService, files)
+
+	// Show the model the repository's recent subjects so it follows the same conventions
+	if n := appConfig.CommitStyleExamples(); n > 0 {
+		if subjects, err := git.GetRecentCommitSubjects(path, n); err == nil {
+			commitOpts.RecentSubjects = subjects
+		}
+	}
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -217,7 +352,7 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Generate commit message (streams to stdout)
-	commitMsg, err := aiService.GenerateCommitMessage(ctx, files)
+	commitMsg, err := aiService.GenerateCommitMessageWithOptions(ctx, files, commitOpts)
 	if err != nil {
 		return fmt.Errorf("commit message generation failed: %w", err)
 	}
@@ -225,12 +360,37 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Println()
 
-	// Ask for confirmation to apply commit
-	fmt.Print("Do you want to apply this commit? (y/N): ")
-	var applyResponse string
-	fmt.Scanln(&applyResponse)
+	printRedactions(aiService)
+	for _, warning := range ai.ValidateConventionalCommit(commitMsg) {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+
+	if commitEdit {
+		commitMsg, err = git.EditMessage(path, commitMsg)
+		if err != nil {
+			return err
+		}
+		if commitMsg == "" {
+			return fmt.Errorf("aborting commit due to empty commit message")
+		}
+	}
+
+	if commitApply {
+		return applyGeneratedCommit(path, commitMsg)
+	}
+
+	// Nobody can answer the prompt in CI or a pipe
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		fmt.Println("Not committing: stdin is not a terminal. Pass --yes to commit without asking.")
+		return nil
+	}
 
-	if applyResponse != "y" && applyResponse != "Y" && applyResponse != "yes" {
+	// Ask for confirmation to apply commit
+	commitMsg, apply, err := confirmCommitMessage(path, commitMsg)
+	if err != nil {
+		return err
+	}
+	if !apply {
 		fmt.Println("Commit cancelled.")
 		return nil
 	}
@@ -241,11 +401,17 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
-	fmt.Println("✅ Commit applied successfully!")
+	if !dryRun {
+		fmt.Println("✅ Commit applied successfully!")
+	}
 	fmt.Println()
 
 	// Ask for confirmation to push
-	fmt.Print("Do you want to push the branch? (y/N): ")
+	if target, err := git.GetPushTarget(path); err == nil && target != "" {
+		fmt.Printf("Do you want to push the branch to %s? (y/N): ", target)
+	} else {
+		fmt.Print("Do you want to push the branch? (y/N): ")
+	}
 	var pushResponse string
 	fmt.Scanln(&pushResponse)
 
@@ -258,13 +424,147 @@ func runAIGenerateCommit(cmd *cobra.Command, args []string) error {
 	fmt.Println("Pushing branch...")
 	err = git.PushBranch(path)
 	if err != nil {
-		return fmt.Errorf("failed to push branch: %w", err)
+		return err
+	}
+	if !dryRun {
+		fmt.Println("✅ Branch pushed successfully!")
 	}
-	fmt.Println("✅ Branch pushed successfully!")
 
 	return nil
 }
 
+// applyGeneratedCommit commits the staged changes with message and prints the new
+// commit's hash, for ai commit --apply
+func applyGeneratedCommit(path, message string) error {
+	if err := git.CreateCommit(path, message); err != nil {
+		return fmt.Errorf("failed to create commit: %w", err)
+	}
+	if dryRun {
+		return nil
+	}
+
+	hash, err := git.GetHeadHash(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✅ Committed %s\n", hash)
+	return nil
+}
+
+// confirmCommitMessage asks whether to commit with message: y applies it, n skips it and
+// e opens it in the editor, then asks again about the edited message
+func confirmCommitMessage(path, message string) (string, bool, error) {
+	for {
+		fmt.Print("Apply this commit? y: yes, n: no, e: edit (y/N/e): ")
+		var response string
+		fmt.Scanln(&response)
+
+		switch strings.ToLower(response) {
+		case "y", "yes":
+			return message, true, nil
+		case "e", "edit":
+			edited, err := git.EditMessage(path, message)
+			if err != nil {
+				return "", false, err
+			}
+			if edited == "" {
+				fmt.Println("Empty message, keeping the previous one.")
+				continue
+			}
+			message = edited
+			fmt.Println()
+			fmt.Println(message)
+			fmt.Println()
+		default:
+			return message, false, nil
+		}
+	}
+}
+
+// loadAIConfig loads the AI configuration along with the .criticaignore of the repository containing path
+func loadAIConfig(path string) (*ai.Config, error) {
+	aiConfig := ai.LoadConfig()
+
+	root, err := git.GetRepoRoot(path)
+	if err != nil {
+		return nil, err
+	}
+	aiConfig.Ignore, err = ai.LoadIgnoreFile(root)
+	if err != nil {
+		return nil, err
+	}
+
+	aiConfig.StreamOutput = os.Stdout
+	aiConfig.Language = appConfig.Language()
+	aiConfig.MaxFiles = appConfig.AIFileLimit()
+	aiConfig.JSONResponseFormat = appConfig.JSONResponseFormat()
+	aiConfig.AnalysisIncludesCommitAndPR = appConfig.AnalysisCommitPR()
+	aiConfig.RedactSecrets = appConfig.RedactSecrets()
+	aiConfig.RedactPatterns, err = ai.CompileRedactPatterns(appConfig.RedactPatterns())
+	if err != nil {
+		return nil, err
+	}
+	if aiDebugEnabled() {
+		aiConfig.DebugLog, err = config.DebugLogPath()
+		if err != nil {
+			return nil, fmt.Errorf("locate AI debug log: %w", err)
+		}
+	}
+	if caCert := appConfig.CACertPath(); caCert != "" {
+		aiConfig.RootCAs, err = ai.LoadCACerts(caCert)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return aiConfig, nil
+}
+
+// aiDebugEnabled reports whether AI_DEBUG or ai_debug turns on prompt/response logging
+func aiDebugEnabled() bool {
+	if enabled, err := strconv.ParseBool(os.Getenv("AI_DEBUG")); err == nil {
+		return enabled
+	}
+	return appConfig.DebugAI()
+}
+
+// printWithheldFiles notes which files .criticaignore keeps out of the prompt and how
+// many more ai_max_files leaves out
+func printWithheldFiles(aiService *ai.Service, files []parser.FileDiff) {
+	if withheld := aiService.WithheldFiles(files); len(withheld) > 0 {
+		fmt.Printf("🔒 %d file(s) withheld from the AI by %s: %s\n", len(withheld), ai.IgnoreFileName, strings.Join(withheld, ", "))
+	}
+	if omitted := aiService.OmittedFileCount(files); omitted > 0 {
+		fmt.Printf("✂️  %d file(s) with the fewest changes left out of the AI context (ai_max_files)\n", omitted)
+	}
+}
+
+// printRedactions notes how many secrets were redacted from the prompt that was just sent,
+// and whether the request had to fall back from streaming
+func printRedactions(aiService *ai.Service) {
+	if n := aiService.LastRedactions(); n > 0 {
+		fmt.Printf("🔒 %d likely secret(s) redacted before sending to the AI\n", n)
+	}
+	if aiService.LastStreamFallback() {
+		fmt.Println("ℹ️  Streaming unavailable from the AI endpoint, used a regular request instead")
+	}
+}
+
+// commitOptionsFromFlags validates --type/--scope and converts them to commit options
+func commitOptionsFromFlags() (ai.CommitOptions, error) {
+	opts := ai.CommitOptions{
+		Type:  strings.ToLower(strings.TrimSpace(commitType)),
+		Scope: strings.TrimSpace(commitScope),
+	}
+	if opts.Scope != "" && opts.Type == "" {
+		return opts, fmt.Errorf("--scope requires --type")
+	}
+	if opts.Type != "" && !slices.Contains(ai.CommitTypes, opts.Type) {
+		return opts, fmt.Errorf("invalid commit type %q (valid: %s)", commitType, strings.Join(ai.CommitTypes, ", "))
+	}
+	return opts, nil
+}
+
 func runAIGeneratePR(cmd *cobra.Command, args []string) error {
 	path := "."
 	targetBranch := ""
@@ -393,7 +693,10 @@ func runAIGeneratePR(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load AI configuration
-	aiConfig := ai.LoadConfig()
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
 	if aiConfig.APIKey == "" {
 		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
 	}
@@ -401,6 +704,10 @@ func runAIGeneratePR(cmd *cobra.Command, args []string) error {
 	// Create AI service
 	aiService := ai.NewService(aiConfig)
 
+	if files, err := parser.ParseDiff(diffOutput); err == nil {
+		printWithheldFiles(aiService, files)
+	}
+
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
@@ -409,13 +716,14 @@ func runAIGeneratePR(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Generate PR description with branch context (streams to stdout)
-	_, err = aiService.GeneratePRDescriptionWithBranches(ctx, diffOutput, currentBranch, targetBranch)
+	_, err = aiService.GeneratePRDescriptionWithBranches(ctx, diffOutput, currentBranch, targetBranch, branchCommitSummary(path, currentBranch, targetBranch))
 	if err != nil {
 		return fmt.Errorf("PR description generation failed: %w", err)
 	}
 
 	fmt.Println()
 	fmt.Println("─" + strings.Repeat("─", 50))
+	printRedactions(aiService)
 	return nil
 }
 
@@ -446,15 +754,23 @@ func runAIImprovements(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse diff: %w", err)
 	}
+	if len(files) == 0 {
+		fmt.Println("No changes to improve")
+		return nil
+	}
 
 	// Load AI configuration
-	aiConfig := ai.LoadConfig()
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
 	if aiConfig.APIKey == "" {
 		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
 	}
 
 	// Create AI service
 	aiService := ai.NewService(aiConfig)
+	printWithheldFiles(aiService, files)
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -471,6 +787,7 @@ func runAIImprovements(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	fmt.Println("─" + strings.Repeat("─", 30))
+	printRedactions(aiService)
 	return nil
 }
 
@@ -501,15 +818,23 @@ func runAIExplain(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse diff: %w", err)
 	}
+	if len(files) == 0 {
+		fmt.Println("No changes to explain")
+		return nil
+	}
 
 	// Load AI configuration
-	aiConfig := ai.LoadConfig()
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
 	if aiConfig.APIKey == "" {
 		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
 	}
 
 	// Create AI service
 	aiService := ai.NewService(aiConfig)
+	printWithheldFiles(aiService, files)
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -526,9 +851,156 @@ func runAIExplain(cmd *cobra.Command, args []string) error {
 
 	fmt.Println()
 	fmt.Println("─" + strings.Repeat("─", 30))
+	printRedactions(aiService)
 	return nil
 }
 
+func runAISummary(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	// Check if we're in a git repository
+	if !git.IsGitRepository(path) {
+		return fmt.Errorf("not a git repository: %s", path)
+	}
+
+	// Get the diff
+	diffOutput, err := git.GetDiff(path, staged)
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	if diffOutput == "" {
+		fmt.Println("No changes to summarize")
+		return nil
+	}
+
+	// Parse the diff
+	files, err := parser.ParseDiff(diffOutput)
+	if err != nil {
+		return fmt.Errorf("failed to parse diff: %w", err)
+	}
+	if len(files) == 0 {
+		fmt.Println("No changes to summarize")
+		return nil
+	}
+
+	// Load AI configuration
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
+	if aiConfig.APIKey == "" {
+		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
+	}
+
+	aiService := ai.NewService(aiConfig)
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// Print only the paragraph so the output can be piped
+	summary, err := aiService.Summarize(ctx, files)
+	if err != nil {
+		return fmt.Errorf("summary failed: %w", err)
+	}
+
+	fmt.Println(summary)
+	return nil
+}
+
+func runAIReview(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	// Check if we're in a git repository
+	if !git.IsGitRepository(path) {
+		return fmt.Errorf("not a git repository: %s", path)
+	}
+
+	// Get the diff
+	diffOutput, err := git.GetDiff(path, staged)
+	if err != nil {
+		return fmt.Errorf("failed to get diff: %w", err)
+	}
+
+	if diffOutput == "" {
+		fmt.Println("No changes to review")
+		return nil
+	}
+
+	// Parse the diff
+	files, err := parser.ParseDiff(diffOutput)
+	if err != nil {
+		return fmt.Errorf("failed to parse diff: %w", err)
+	}
+	if len(files) == 0 {
+		fmt.Println("No changes to review")
+		return nil
+	}
+
+	// Load AI configuration
+	aiConfig, err := loadAIConfig(path)
+	if err != nil {
+		return err
+	}
+	if aiConfig.APIKey == "" {
+		return fmt.Errorf("OPENAI_API_KEY environment variable not set")
+	}
+
+	// Create AI service
+	aiService := ai.NewService(aiConfig)
+	printWithheldFiles(aiService, files)
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	fmt.Println("🤖 Reviewing changes...")
+	fmt.Println()
+
+	comments, err := aiService.ReviewDiff(ctx, files)
+	if err != nil {
+		return fmt.Errorf("AI review failed: %w", err)
+	}
+
+	printRedactions(aiService)
+	displayReviewComments(comments)
+	return nil
+}
+
+func displayReviewComments(comments []ai.ReviewComment) {
+	fmt.Println("🔍 Review Comments")
+	fmt.Println("─" + strings.Repeat("─", 50))
+
+	if len(comments) == 0 {
+		fmt.Println("No review comments.")
+		return
+	}
+
+	currentFile := ""
+	for _, comment := range comments {
+		if comment.File != currentFile {
+			if currentFile != "" {
+				fmt.Println()
+			}
+			fmt.Printf("📄 %s\n", comment.File)
+			currentFile = comment.File
+		}
+
+		severity := comment.Severity
+		if severity == "" {
+			severity = "info"
+		}
+		fmt.Printf("  L%d [%s] %s\n", comment.Line, severity, comment.Comment)
+	}
+}
+
 func displayAnalysisResult(result *ai.AnalysisResult) {
 	fmt.Println("📊 Analysis Results")
 	fmt.Println("─" + strings.Repeat("─", 50))
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/danielss-dev/critica/internal/config"
+	"github.com/danielss-dev/critica/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the critica config file",
+	// The config file is what these commands fix, so don't fail loading it first
+	PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in your editor",
+	Long: `Open the config file in the editor git commit would use (GIT_EDITOR, core.editor,
+VISUAL, EDITOR, then git's default), creating it from a template first if it doesn't
+exist. The file is validated after the editor exits and any error is reported, so
+mistakes show up before the next run.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configEditCmd)
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("failed to find the config directory: %w", err)
+	}
+
+	created, err := config.CreateIfMissing(path)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(os.Stderr, "Created %s\n", path)
+	}
+
+	// Use the editor git commit would, so this and ai commit --edit agree
+	if err := git.EditFile(".", path); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if _, err := config.Parse(data); err != nil {
+		return fmt.Errorf("%s is invalid: %w", path, err)
+	}
+
+	fmt.Printf("Saved %s\n", path)
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/ui"
+)
+
+// logPatchInput is git log -p output for two commits, the first changing two files
+const logPatchInput = `commit 2817b2a2de42ca200d073298ec83fe3d2346302a
+Author: critica <critica@example.com>
+Date:   Fri Oct 16 07:33:06 2026 +0000
+
+    second
+
+diff --git a/a.txt b/a.txt
+index 7898192..422c2b7 100644
+--- a/a.txt
++++ b/a.txt
+@@ -1 +1,2 @@
+ a
++b
+diff --git a/c.txt b/c.txt
+new file mode 100644
+index 0000000..f2ad6c7
+--- /dev/null
++++ b/c.txt
+@@ -0,0 +1 @@
++c
+
+commit ce64f5696bd9787f359aecd028d2c6f6e21fab70
+Author: critica <critica@example.com>
+Date:   Fri Oct 16 07:33:06 2026 +0000
+
+    first commit
+    
+    - body line
+
+diff --git a/a.txt b/a.txt
+new file mode 100644
+index 0000000..7898192
+--- /dev/null
++++ b/a.txt
+@@ -0,0 +1 @@
++a
+`
+
+// TestRenderPagerInputLogPatch checks that the commits of git log -p output are passed
+// through around the rendered file diffs, in their original order
+func TestRenderPagerInputLogPatch(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		input string
+	}{
+		{"plain", logPatchInput},
+		{"colored", strings.ReplaceAll(strings.ReplaceAll(logPatchInput, "commit ", "\x1b[33mcommit "), "\ndiff --git", "\n\x1b[1mdiff --git")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := renderPagerInput(&out, tt.input, ui.RendererOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			got := out.String()
+
+			if strings.Contains(got, "diff --git") || strings.Contains(got, "@@ -") {
+				t.Errorf("file diffs were passed through instead of rendered:\n%s", got)
+			}
+			// Text keeps its colors; everything else appears in input order
+			want := []string{
+				"commit 2817b2a2de42ca200d073298ec83fe3d2346302a\n",
+				"Author: critica <critica@example.com>\n",
+				"    second\n",
+				"a.txt", "c.txt",
+				"commit ce64f5696bd9787f359aecd028d2c6f6e21fab70\n",
+				"    first commit\n",
+				"    - body line\n",
+				"a.txt",
+			}
+			rest := got
+			for _, part := range want {
+				i := strings.Index(rest, part)
+				if i < 0 {
+					t.Fatalf("%q missing or out of order in:\n%s", part, got)
+				}
+				rest = rest[i+len(part):]
+			}
+		})
+	}
+}
+
+// TestRenderPagerInputWithoutDiff checks that input without a diff is passed through
+func TestRenderPagerInputWithoutDiff(t *testing.T) {
+	input := "\x1b[33m2817b2a\x1b[m second\nce64f56 first commit\n"
+	var out bytes.Buffer
+	if err := renderPagerInput(&out, input, ui.RendererOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != input {
+		t.Errorf("output = %q, want the input unchanged", out.String())
+	}
+}
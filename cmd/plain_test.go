@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/git"
+	"github.com/danielss-dev/critica/internal/parser"
+	"github.com/danielss-dev/critica/internal/ui"
+)
+
+// runGit runs git in dir with a fixed identity and returns its output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=critica", "-c", "user.email=critica@example.com",
+		"-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// modeChangeRepo commits a few files and then changes them in the working tree: run.sh
+// only becomes executable, edit.sh also changes three lines, big.txt changes five lines
+// and small.txt one
+func modeChangeRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	numbered := func(prefix string, n int) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			fmt.Fprintf(&b, "%s %d\n", prefix, i)
+		}
+		return b.String()
+	}
+
+	runGit(t, dir, "init", "-q")
+	write("run.sh", "echo run\n")
+	write("edit.sh", numbered("echo", 3))
+	write("big.txt", numbered("line", 5))
+	write("small.txt", "small\n")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "base")
+
+	write("edit.sh", numbered("echo edited", 3))
+	write("big.txt", numbered("changed", 5))
+	write("small.txt", "smaller\n")
+	for _, name := range []string{"run.sh", "edit.sh"} {
+		if err := os.Chmod(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// TestPlainModeChange checks that --plain prints mode changes exactly as git diff does
+func TestPlainModeChange(t *testing.T) {
+	dir := modeChangeRepo(t)
+	want := runGit(t, dir, "diff", "--src-prefix=a/", "--dst-prefix=b/", "--", "run.sh", "edit.sh")
+
+	diffOutput, err := git.GetDiffForPaths([]string{filepath.Join(dir, "run.sh"), filepath.Join(dir, "edit.sh")}, git.DiffModeUnstaged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := parser.ParseDiff(diffOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := ui.NewRenderer(ui.RendererOptions{}).RenderPlain(&out, files); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want {
+		t.Errorf("--plain output differs from git diff:\ngot:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+// TestLimitDiffModeChange checks that cutting a diff down with --max-files keeps the mode
+// change of a kept file
+func TestLimitDiffModeChange(t *testing.T) {
+	dir := modeChangeRepo(t)
+	want := runGit(t, dir, "diff", "--src-prefix=a/", "--dst-prefix=b/", "--", "big.txt", "edit.sh")
+
+	diffOutput, err := git.GetDiffForMode(dir, git.DiffModeUnstaged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := maxFiles
+	maxFiles = 2
+	t.Cleanup(func() { maxFiles = previous })
+	got, err := limitDiff(diffOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("limited diff differs from git diff of the kept files:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestPlainColorWhenPiped checks that piped --plain output has no escape codes unless
+// --force-color is given
+func TestPlainColorWhenPiped(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	files, err := parser.ParseDiff("diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n@@ -1 +1 @@\n-package main\n+package app\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name       string
+		forceColor bool
+		wantEscape bool
+	}{
+		{"piped", false, false},
+		{"forced", true, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := plainRendererOptions(ui.RendererOptions{UseColor: true, ForceColor: tt.forceColor}, w)
+			var out bytes.Buffer
+			if err := ui.NewRenderer(opts).RenderPlain(&out, files); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(out.String(), "\x1b["); got != tt.wantEscape {
+				t.Errorf("output has escape codes = %v, want %v:\n%q", got, tt.wantEscape, out.String())
+			}
+		})
+	}
+}
+
+// TestPlainContentOmitted checks that an untracked file listed without its content is
+// printed the way git prints a binary file, without critica's own header line
+func TestPlainContentOmitted(t *testing.T) {
+	files := []parser.FileDiff{{OldPath: "big.bin", NewPath: "big.bin", IsNew: true, IsUntracked: true, ContentOmitted: true}}
+	var out bytes.Buffer
+	if err := ui.NewRenderer(ui.RendererOptions{}).RenderPlain(&out, files); err != nil {
+		t.Fatal(err)
+	}
+	want := "diff --git a/big.bin b/big.bin\nnew file mode 100644\nBinary files /dev/null and b/big.bin differ\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -1,54 +1,104 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/danielss-dev/critica/internal/ai"
 	"github.com/danielss-dev/critica/internal/config"
 	"github.com/danielss-dev/critica/internal/git"
 	"github.com/danielss-dev/critica/internal/parser"
 	"github.com/danielss-dev/critica/internal/ui"
+	"github.com/danielss-dev/critica/pkg/critica"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
+// progressMinFiles is the number of files above which parsing progress is reported
+const progressMinFiles = 100
+
 var (
 	staged      bool
+	unstaged    bool
+	since       int
 	cached      bool
 	noColor     bool
+	forceColor  bool
 	unified     bool
 	interactive bool
 	aiEnabled   bool
+	dryRun      bool
+	reverse     bool
+	funcContext bool
+	nameStatus  bool
+	plain       bool
+	fullPaths   bool
+	wrap        bool
+	themePrev   bool
+	noResume    bool
+	summary     bool
+	compact     bool
+	filesFrom   string
+	pagerMode   bool
+	maxFiles    int
+	pathStyle   string
 
 	appConfig *config.Config
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "critica [path]",
+	Use:   "critica [path...]",
 	Short: "A beautiful git diff viewer for the terminal",
 	Long: `Critica displays git diffs in a beautiful split-screen format.
 
 You can view diffs for:
   - The entire repository (no arguments)
-  - A specific file or directory (provide path as argument)
+  - Specific files or directories (provide paths as arguments)
 
 Examples:
   critica                    # Show diff for entire repo
   critica src/main.go        # Show diff for specific file
   critica src/               # Show diff for directory
+  critica src/ cmd/ main.go  # Show diff for several paths
+  critica --files-from review.txt  # Show diff for the paths listed in a file
   critica --staged           # Show staged changes
-  critica --cached           # Show cached changes (alias for --staged)`,
-	Args: cobra.MaximumNArgs(1),
+  critica --cached           # Show cached changes (alias for --staged)
+  critica --unstaged         # Show only unstaged changes
+  critica --since 3          # Show the last three commits plus the working tree
+  critica --name-status      # List changed paths with their status only`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runDiff,
 }
 
 func init() {
 	rootCmd.Flags().BoolVarP(&staged, "staged", "s", false, "Show only staged changes")
 	rootCmd.Flags().BoolVarP(&cached, "cached", "c", false, "Show only cached changes (same as --staged)")
+	rootCmd.Flags().IntVar(&since, "since", 0, "Show everything changed in the last N commits plus the working tree (git diff HEAD~N)")
+	rootCmd.Flags().BoolVar(&unstaged, "unstaged", false, "Show only unstaged changes (working tree against the index)")
+	rootCmd.Flags().StringVar(&filesFrom, "files-from", "", "Read newline-separated paths to diff from a file (# starts a comment)")
+	rootCmd.Flags().IntVar(&maxFiles, "max-files", config.DefaultMaxFiles, "Show only the N files with the most changed lines when more have changed (0 shows all)")
 	rootCmd.Flags().BoolVar(&noColor, "no-color", false, "Disable color output")
+	rootCmd.Flags().BoolVar(&forceColor, "force-color", false, "Keep color output when it is not a terminal (also set by CLICOLOR_FORCE)")
 	rootCmd.Flags().BoolVarP(&unified, "unified", "u", false, "Show unified diff view (non-split)")
 	rootCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Interactive mode with fuzzy finder and collapsible files")
 	rootCmd.Flags().BoolVar(&aiEnabled, "ai", false, "Enable AI analysis and suggestions")
+	rootCmd.Flags().BoolVarP(&reverse, "reverse", "R", false, "Swap the sides of the diff to show what reverting the changes would look like")
+	rootCmd.Flags().BoolVarP(&funcContext, "function-context", "W", false, "Show whole functions around changes instead of a few lines of context")
+	rootCmd.Flags().StringVar(&pathStyle, "path-style", ui.PathStyleRepo, "Show paths relative to the repository root (repo), the current directory (relative) or as absolute paths (absolute)")
+	rootCmd.Flags().BoolVar(&fullPaths, "full-paths", false, "Show whole paths in the interactive file list instead of abbreviating long ones")
+	rootCmd.Flags().BoolVar(&wrap, "wrap", false, "Wrap long lines onto extra rows in the split view instead of truncating them")
+	rootCmd.Flags().BoolVar(&compact, "compact", false, "Drop the blank lines around file headers and between hunks")
+	rootCmd.Flags().BoolVar(&pagerMode, "pager-mode", false, "Render a diff read from stdin without running git, for use as git's core.pager")
+	rootCmd.Flags().BoolVar(&themePrev, "theme-preview", false, "Render a sample diff in every diff style to compare them")
+	rootCmd.Flags().BoolVar(&noResume, "no-resume", false, "Don't restore or save the unified and preview toggles from the last interactive session")
+	rootCmd.Flags().BoolVar(&summary, "summary-footer", false, "End the diff with a line totalling files changed, insertions and deletions")
+	rootCmd.Flags().BoolVar(&plain, "plain", false, "Print the diff in git's unified format, with syntax highlighting as the only decoration")
+	rootCmd.Flags().BoolVar(&nameStatus, "name-status", false, "List changed files with their status (M, A, D, R) instead of the diff")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print git commands that would stage, commit or push instead of running them")
 	rootCmd.PersistentPreRunE = applyConfig
 }
 
@@ -60,25 +110,67 @@ func Execute() {
 }
 
 func runDiff(cmd *cobra.Command, args []string) error {
-	// Get the path (default to current directory)
-	path := "."
-	if len(args) > 0 {
-		path = args[0]
+	// Get the paths (default to current directory). The first one locates the
+	// repository; all of them limit the diff.
+	paths := args
+	if filesFrom != "" {
+		listed, err := readPathsFile(filesFrom)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, listed...)
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	path := paths[0]
+
+	switch pathStyle {
+	case ui.PathStyleRepo, ui.PathStyleRelative, ui.PathStyleAbsolute:
+	default:
+		return fmt.Errorf("invalid --path-style %q: must be relative, repo or absolute", pathStyle)
+	}
+
+	// Show the sample diff in each style; no repository is needed
+	if themePrev {
+		return ui.RenderThemePreview(os.Stdout, newRendererOptions())
+	}
+
+	// git already ran the diff and pages the output
+	if pagerMode {
+		return runPager()
 	}
 
 	// Determine if we should show staged changes
 	showStaged := staged || cached
+	if showStaged && unstaged {
+		return fmt.Errorf("--staged and --unstaged cannot be used together: omit both to show all changes")
+	}
+	if maxFiles < 0 {
+		return fmt.Errorf("invalid --max-files %d: must not be negative", maxFiles)
+	}
+	sinceRef, err := sinceRefFromFlags(cmd)
+	if err != nil {
+		return err
+	}
 
-	// Check if we're in a git repository
-	if !git.IsGitRepository(path) {
-		return fmt.Errorf("not a git repository: %s", path)
+	git.Reverse = reverse
+	git.FunctionContext = funcContext
+
+	// Check that every argument is a path inside a git repository
+	for _, p := range paths {
+		if !git.IsGitRepository(p) {
+			return fmt.Errorf("not a git repository: %s", p)
+		}
 	}
 
 	// Select diff mode
 	diffMode := git.DiffModeAll
 	if showStaged {
 		diffMode = git.DiffModeStaged
-	} else if appConfig != nil {
+	} else if unstaged {
+		diffMode = git.DiffModeUnstaged
+	} else if appConfig != nil && sinceRef == "" {
 		switch appConfig.DiffMode {
 		case config.DiffModeAll:
 			diffMode = git.DiffModeAll
@@ -89,8 +181,19 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if sinceRef != "" {
+		if err := git.VerifyCommit(path, sinceRef); err != nil {
+			return fmt.Errorf("--since %d: %w (the branch has fewer commits)", since, err)
+		}
+	}
+
 	// Get the git diff
-	diffOutput, err := git.GetDiffForMode(path, diffMode)
+	var diffOutput string
+	if sinceRef != "" {
+		diffOutput, err = git.GetDiffForPathsAgainstRef(paths, sinceRef)
+	} else {
+		diffOutput, err = git.GetDiffForPaths(paths, diffMode)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get git diff: %w", err)
 	}
@@ -101,67 +204,402 @@ func runDiff(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Parse the diff output
-	files, err := parser.ParseDiff(diffOutput)
+	// Cut huge changesets down to the largest files
+	diffOutput, err = limitDiff(diffOutput)
 	if err != nil {
-		return fmt.Errorf("failed to parse diff: %w", err)
+		return err
 	}
 
-	rendererOpts := ui.RendererOptions{
-		UseColor: !noColor,
-		Unified:  unified,
+	rendererOpts := newRendererOptions()
+	if pathStyle != ui.PathStyleRepo {
+		root, err := git.GetRepoRoot(path)
+		if err != nil {
+			return err
+		}
+		rendererOpts.RepoRoot = root
 	}
 
-	if appConfig != nil {
-		rendererOpts.DiffStyle = appConfig.DiffStyle
-		rendererOpts.AddedTextColor = appConfig.AddedTextColor
-		rendererOpts.DeletedTextColor = appConfig.DeletedTextColor
+	// List only the changed paths and their status
+	if nameStatus {
+		files, err := parser.ParseDiff(diffOutput)
+		if err != nil {
+			return fmt.Errorf("failed to parse diff: %w", err)
+		}
+		return ui.NewRenderer(rendererOpts).RenderNameStatus(os.Stdout, files)
+	}
+
+	// Re-emit the diff as git-style text
+	if plain {
+		files, err := parser.ParseDiff(diffOutput)
+		if err != nil {
+			return fmt.Errorf("failed to parse diff: %w", err)
+		}
+		return ui.NewRenderer(plainRendererOptions(rendererOpts, os.Stdout)).RenderPlain(os.Stdout, files)
 	}
 
 	// Run in interactive mode or static mode
 	if interactive {
+		// Parse the diff output
+		files, err := parseDiffWithProgress(diffOutput)
+		if err != nil {
+			return fmt.Errorf("failed to parse diff: %w", err)
+		}
+
 		var stagedFiles []parser.FileDiff
 		var unstagedFiles []parser.FileDiff
+		var untrackedFiles []parser.FileDiff
 
 		if showStaged {
 			stagedFiles = files
 		} else {
-			stagedOutput, err := git.GetDiffForMode(path, git.DiffModeStaged)
-			if err != nil {
-				return fmt.Errorf("failed to get staged diff: %w", err)
-			}
-			stagedFiles, err = parser.ParseDiff(stagedOutput)
-			if err != nil {
-				return fmt.Errorf("failed to parse staged diff: %w", err)
-			}
-
-			unstagedOutput, err := git.GetDiffForMode(path, git.DiffModeUnstaged)
+			stagedFiles, unstagedFiles, untrackedFiles, err = loadFilterFiles(paths)
 			if err != nil {
-				return fmt.Errorf("failed to get unstaged diff: %w", err)
-			}
-			unstagedFiles, err = parser.ParseDiff(unstagedOutput)
-			if err != nil {
-				return fmt.Errorf("failed to parse unstaged diff: %w", err)
+				return err
 			}
 		}
 
 		// Initialize AI service for interactive mode
 		var aiService *ai.Service
-		aiConfig := ai.LoadConfig()
+		aiConfig, err := loadAIConfig(path)
+		if err != nil {
+			return err
+		}
+		// Responses must not be echoed while the TUI owns the terminal
+		aiConfig.StreamOutput = nil
 		if aiConfig.APIKey != "" {
 			aiService = ai.NewService(aiConfig)
 		}
 
-		return ui.RunInteractive(files, stagedFiles, unstagedFiles, rendererOpts, aiService)
+		interactiveOpts := ui.InteractiveOptions{
+			CollapsePaths:       appConfig.CollapsePatterns(),
+			CommitNeverAddAll:   appConfig.NeverAddAll(),
+			CommitStyleExamples: appConfig.CommitStyleExamples(),
+			DryRun:              dryRun,
+			FileLabels:          appConfig.ShowFileLabels(),
+			FullPaths:           fullPaths,
+			RepoPath:            path,
+			SinceRef:            sinceRef,
+			DiffMode:            diffMode,
+			LoadDiff: func(mode git.DiffMode) (ui.DiffSet, error) {
+				return loadDiffSet(paths, mode, sinceRef)
+			},
+		}
+
+		// Restore the view toggles from the last session unless --unified was given
+		if !noResume {
+			// The saved preferences are a convenience; a damaged file must not block the session
+			state, err := config.LoadState()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "critica: ignoring saved view preferences: %v\n", err)
+			}
+			if state.Unified != nil && !cmd.Flags().Changed("unified") {
+				rendererOpts.Unified = *state.Unified
+			}
+			if state.PreviewCollapsed != nil {
+				interactiveOpts.PreviewCollapsed = *state.PreviewCollapsed
+			}
+			interactiveOpts.SaveView = func(unified, previewCollapsed bool) error {
+				return config.SaveState(&config.State{Unified: &unified, PreviewCollapsed: &previewCollapsed})
+			}
+		}
+
+		return ui.RunInteractive(files, stagedFiles, unstagedFiles, untrackedFiles, rendererOpts, interactiveOpts, aiService)
+	}
+
+	return renderStatic(os.Stdout, diffOutput, rendererOpts)
+}
+
+// renderStatic renders the diff to w through the public library entry point
+func renderStatic(w io.Writer, diffOutput string, rendererOpts ui.RendererOptions) error {
+	return critica.RenderDiff(w, diffOutput, critica.Options{
+		UseColor:         rendererOpts.UseColor,
+		ForceColor:       rendererOpts.ForceColor,
+		Unified:          rendererOpts.Unified,
+		DiffStyle:        rendererOpts.DiffStyle,
+		AddedTextColor:   rendererOpts.AddedTextColor,
+		DeletedTextColor: rendererOpts.DeletedTextColor,
+		SplitMinWidth:    rendererOpts.SplitMinWidth,
+		MovedMinLines:    rendererOpts.MovedMinLines,
+		Wrap:             rendererOpts.Wrap,
+		Compact:          rendererOpts.Compact,
+		MaxLineLength:    rendererOpts.MaxLineLength,
+		HighlightLimit:   rendererOpts.HighlightLimit,
+		SyntaxStyles:     rendererOpts.SyntaxStyles,
+		SummaryFooter:    rendererOpts.SummaryFooter,
+		ColumnSeparator:  rendererOpts.ColumnSeparator,
+		SkipMarker:       rendererOpts.SkipMarker,
+		PathStyle:        rendererOpts.PathStyle,
+		RepoRoot:         rendererOpts.RepoRoot,
+	})
+}
+
+// runPager renders a diff read from stdin without running git, for use as git's pager.
+// Everything around the file diffs, such as the commits of git log -p, and input without
+// a diff, such as git branch output, is passed through unchanged.
+func runPager() error {
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		return fmt.Errorf("--pager-mode reads a diff on stdin: set it as git's pager with git config core.pager 'critica --pager-mode'")
+	}
+	if interactive {
+		return fmt.Errorf("--pager-mode cannot be combined with --interactive")
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read diff from stdin: %w", err)
+	}
+
+	rendererOpts := newRendererOptions()
+	if pathStyle != ui.PathStyleRepo {
+		// git runs its pager inside the repository; keep repo-relative paths if it didn't
+		rendererOpts.RepoRoot, _ = git.GetRepoRoot(".")
 	}
+	return renderPagerInput(os.Stdout, string(input), rendererOpts)
+}
+
+// pagerSegment is a run of pager input lines that is either all file diffs or all other
+// text, such as commit headers and messages
+type pagerSegment struct {
+	text   string
+	isDiff bool
+}
 
-	// Render the diff statically
-	renderer := ui.NewRenderer(rendererOpts)
-	renderer.Render(files)
+// pagerDiffLinePrefixes start the lines that can follow a "diff --git" line within the
+// same file diff: the extended header lines and the hunk lines
+var pagerDiffLinePrefixes = []string{
+	"diff --git ", "index ", "old mode ", "new mode ", "new file mode ", "deleted file mode ",
+	"similarity index ", "dissimilarity index ", "rename from ", "rename to ", "copy from ",
+	"copy to ", "Binary files ", "--- ", "+++ ", "@@ ", "+", "-", " ", `\`,
+	parser.UntrackedFileHeader, parser.ContentOmittedHeader,
+}
 
+// splitPagerInput splits pager input into file diffs and the text between them. Diff
+// segments hold the lines without git's colors, for the parser; text segments keep the
+// lines as they came in. A diff ends at the first line no file diff can contain, such as
+// the blank line before the next commit of git log -p.
+func splitPagerInput(input string) []pagerSegment {
+	var segments []pagerSegment
+	var current strings.Builder
+	inDiff := false
+	flush := func() {
+		if current.Len() > 0 {
+			segments = append(segments, pagerSegment{text: current.String(), isDiff: inDiff})
+			current.Reset()
+		}
+	}
+
+	for _, line := range strings.SplitAfter(input, "\n") {
+		if line == "" {
+			continue
+		}
+		plainLine := ansi.Strip(line)
+		content := strings.TrimRight(plainLine, "\r\n")
+		isDiffLine := strings.HasPrefix(content, "diff --git ")
+		if inDiff && !isDiffLine {
+			for _, prefix := range pagerDiffLinePrefixes {
+				if strings.HasPrefix(content, prefix) {
+					isDiffLine = true
+					break
+				}
+			}
+		}
+
+		if isDiffLine != inDiff {
+			flush()
+			inDiff = isDiffLine
+		}
+		if inDiff {
+			current.WriteString(plainLine)
+		} else {
+			current.WriteString(line)
+		}
+	}
+	flush()
+	return segments
+}
+
+// renderPagerInput writes pager input to w with its file diffs rendered and the text
+// between them unchanged
+func renderPagerInput(w io.Writer, input string, rendererOpts ui.RendererOptions) error {
+	for _, segment := range splitPagerInput(input) {
+		if !segment.isDiff {
+			if _, err := io.WriteString(w, segment.text); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := renderStatic(w, segment.text, rendererOpts); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// plainRendererOptions turns color off for --plain output that isn't going to a terminal,
+// since piped output is meant for grep and other tools, unless --force-color asks for it
+func plainRendererOptions(opts ui.RendererOptions, out *os.File) ui.RendererOptions {
+	if !opts.ForceColor && !isatty.IsTerminal(out.Fd()) {
+		opts.UseColor = false
+	}
+	return opts
+}
+
+// limitDiff keeps the maxFiles files with the most changed lines when the diff has more,
+// warning on stderr that the rest are hidden
+func limitDiff(diffOutput string) (string, error) {
+	if maxFiles <= 0 || strings.Count(diffOutput, "\ndiff --git ")+1 <= maxFiles {
+		return diffOutput, nil
+	}
+
+	files, err := parser.ParseDiff(diffOutput)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse diff: %w", err)
+	}
+	if len(files) <= maxFiles {
+		return diffOutput, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "critica: %d files changed, showing the %d with the most changed lines. Pass paths to narrow the diff or raise --max-files (0 shows all).\n", len(files), maxFiles)
+	return parser.FilesToUnified(parser.KeepLargest(files, maxFiles)), nil
+}
+
+// loadFilterFiles loads the staged, unstaged and untracked changes the interactive file
+// filter switches between
+func loadFilterFiles(paths []string) (staged, unstaged, untracked []parser.FileDiff, err error) {
+	stagedOutput, err := git.GetDiffForPaths(paths, git.DiffModeStaged)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get staged diff: %w", err)
+	}
+	staged, err = parser.ParseDiff(stagedOutput)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse staged diff: %w", err)
+	}
+
+	unstagedOutput, err := git.GetDiffForPaths(paths, git.DiffModeUnstaged)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get unstaged diff: %w", err)
+	}
+	unstaged, err = parser.ParseDiff(unstagedOutput)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse unstaged diff: %w", err)
+	}
+	unstaged, untracked = splitUntracked(unstaged)
+	return parser.KeepLargest(staged, maxFiles), parser.KeepLargest(unstaged, maxFiles), parser.KeepLargest(untracked, maxFiles), nil
+}
+
+// loadDiffSet re-runs git for an interactive session switching its diff source to mode.
+// The all-changes source stays against ref when --since set one.
+func loadDiffSet(paths []string, mode git.DiffMode, ref string) (ui.DiffSet, error) {
+	var diffOutput string
+	var err error
+	if ref != "" && mode == git.DiffModeAll {
+		diffOutput, err = git.GetDiffForPathsAgainstRef(paths, ref)
+	} else {
+		diffOutput, err = git.GetDiffForPaths(paths, mode)
+	}
+	if err != nil {
+		return ui.DiffSet{}, fmt.Errorf("failed to get git diff: %w", err)
+	}
+	files, err := parser.ParseDiff(diffOutput)
+	if err != nil {
+		return ui.DiffSet{}, fmt.Errorf("failed to parse diff: %w", err)
+	}
+
+	set := ui.DiffSet{All: parser.KeepLargest(files, maxFiles)}
+	set.Staged, set.Unstaged, set.Untracked, err = loadFilterFiles(paths)
+	if err != nil {
+		return ui.DiffSet{}, err
+	}
+	return set, nil
+}
+
+// readPathsFile reads the paths listed one per line in name, skipping blank lines and
+// lines starting with #
+func readPathsFile(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --files-from: %w", err)
+	}
+	defer file.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read --files-from: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("--files-from %s lists no paths", name)
+	}
+	return paths, nil
+}
+
+// newRendererOptions builds the renderer options from the flags and the config file
+func newRendererOptions() ui.RendererOptions {
+	rendererOpts := ui.RendererOptions{
+		UseColor:      !noColor,
+		ForceColor:    forceColor,
+		Unified:       unified,
+		Wrap:          wrap,
+		Compact:       compact,
+		SummaryFooter: summary,
+		PathStyle:     pathStyle,
+	}
+
+	if appConfig != nil {
+		rendererOpts.DiffStyle = appConfig.DiffStyle
+		rendererOpts.AddedTextColor = appConfig.AddedTextColor
+		rendererOpts.DeletedTextColor = appConfig.DeletedTextColor
+		rendererOpts.SplitMinWidth = appConfig.SplitThreshold()
+		rendererOpts.MovedMinLines = appConfig.MovedThreshold()
+		rendererOpts.MaxLineLength = appConfig.LineLengthLimit()
+		rendererOpts.HighlightLimit = appConfig.HighlightLineLimit()
+		rendererOpts.SyntaxStyles = appConfig.SyntaxThemeOverrides
+		rendererOpts.ColumnSeparator = appConfig.ColumnSeparator
+		rendererOpts.SkipMarker = appConfig.SkipMarker
+	}
+	return rendererOpts
+}
+
+// splitUntracked separates untracked files from the tracked changes they are listed with
+func splitUntracked(files []parser.FileDiff) (tracked, untracked []parser.FileDiff) {
+	for _, file := range files {
+		if file.IsUntracked {
+			untracked = append(untracked, file)
+		} else {
+			tracked = append(tracked, file)
+		}
+	}
+	return tracked, untracked
+}
+
+// parseDiffWithProgress parses a diff, reporting progress on stderr for large diffs
+// before the interactive UI takes over the terminal
+func parseDiffWithProgress(diffOutput string) ([]parser.FileDiff, error) {
+	if !isatty.IsTerminal(os.Stderr.Fd()) {
+		return parser.ParseDiff(diffOutput)
+	}
+
+	reported := false
+	files, err := parser.ParseDiffWithProgress(diffOutput, func(done, total int) {
+		if total < progressMinFiles {
+			return
+		}
+		reported = true
+		fmt.Fprintf(os.Stderr, "\rParsing diff... %d/%d files", done, total)
+	})
+	if reported {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+	return files, err
+}
+
 func applyConfig(cmd *cobra.Command, _ []string) error {
 	if appConfig != nil {
 		return nil
@@ -173,6 +611,11 @@ func applyConfig(cmd *cobra.Command, _ []string) error {
 	}
 
 	appConfig = cfg
+	git.UntrackedMaxBytes = cfg.UntrackedSizeLimit()
+
+	if dryRun {
+		git.DryRun = os.Stdout
+	}
 
 	applyBool := func(flagName string, target *bool, value *bool) {
 		if value == nil {
@@ -187,12 +630,56 @@ func applyConfig(cmd *cobra.Command, _ []string) error {
 	applyBool("interactive", &interactive, cfg.Interactive)
 	applyBool("unified", &unified, cfg.Unified)
 	applyBool("no-color", &noColor, cfg.NoColor)
+	applyBool("force-color", &forceColor, cfg.ForceColor)
+	if !cmd.Flags().Changed("force-color") {
+		if value := os.Getenv("CLICOLOR_FORCE"); value != "" && value != "0" {
+			forceColor = true
+		}
+	}
+	applyBool("wrap", &wrap, cfg.Wrap)
+	applyBool("compact", &compact, cfg.Compact)
+	if !cmd.Flags().Changed("max-files") {
+		maxFiles = cfg.FileLimit()
+	}
 
 	if cfg.DiffMode == config.DiffModeStaged {
-		if !cmd.Flags().Changed("staged") && !cmd.Flags().Changed("cached") {
+		// --since diffs against a commit, so the staged default doesn't apply to it
+		if !cmd.Flags().Changed("staged") && !cmd.Flags().Changed("cached") && !cmd.Flags().Changed("unstaged") && !cmd.Flags().Changed("since") {
 			staged = true
 		}
 	}
 
+	// The theme preview, pager mode and shell completion never run git
+	if themePrev || pagerMode || strings.HasPrefix(cmd.CommandPath(), rootCmd.Name()+" completion") {
+		return nil
+	}
+	return checkGit()
+}
+
+// sinceRefFromFlags returns the ref --since diffs against, or "" when it isn't given. Only
+// --staged, --cached and --unstaged on the command line conflict with it, not a diff_mode
+// from the config file.
+func sinceRefFromFlags(cmd *cobra.Command) (string, error) {
+	if !cmd.Flags().Changed("since") {
+		return "", nil
+	}
+	if since < 0 {
+		return "", fmt.Errorf("invalid --since %d: must not be negative", since)
+	}
+	if cmd.Flags().Changed("staged") || cmd.Flags().Changed("cached") || cmd.Flags().Changed("unstaged") {
+		return "", fmt.Errorf("--since cannot be combined with --staged or --unstaged")
+	}
+	return fmt.Sprintf("HEAD~%d", since), nil
+}
+
+// checkGit fails when git is missing and warns when it is older than critica supports
+func checkGit() error {
+	version, err := git.InstalledVersion()
+	if err != nil {
+		return err
+	}
+	if version.Less(git.MinVersion) {
+		fmt.Fprintf(os.Stderr, "critica: git %s is older than %s; some features may not work as expected, consider upgrading\n", version, git.MinVersion)
+	}
 	return nil
 }
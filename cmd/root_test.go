@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newSinceTestCommand returns a command with the flags --since is checked against,
+// bound to the package flag variables like rootCmd's
+func newSinceTestCommand(t *testing.T, configJSON string, args ...string) *cobra.Command {
+	t.Helper()
+
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	if err := os.MkdirAll(filepath.Join(configHome, "critica"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configHome, "critica", "config.json"), []byte(configJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	appConfig = nil
+	staged, cached, unstaged, since = false, false, false, 0
+	t.Cleanup(func() {
+		appConfig = nil
+		staged, cached, unstaged, since = false, false, false, 0
+	})
+
+	cmd := &cobra.Command{Use: "critica"}
+	cmd.Flags().IntVar(&since, "since", 0, "")
+	cmd.Flags().BoolVarP(&staged, "staged", "s", false, "")
+	cmd.Flags().BoolVarP(&cached, "cached", "c", false, "")
+	cmd.Flags().BoolVar(&unstaged, "unstaged", false, "")
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(cmd, nil); err != nil {
+		t.Fatalf("applyConfig: %v", err)
+	}
+	return cmd
+}
+
+func TestSinceWithStagedDiffModeInConfig(t *testing.T) {
+	cmd := newSinceTestCommand(t, `{"diff_mode": "staged"}`, "--since", "2")
+
+	ref, err := sinceRefFromFlags(cmd)
+	if err != nil {
+		t.Fatalf("sinceRefFromFlags: %v", err)
+	}
+	if ref != "HEAD~2" {
+		t.Errorf("ref = %q, want HEAD~2", ref)
+	}
+	if staged {
+		t.Error("diff_mode staged was applied even though --since was given")
+	}
+}
+
+func TestSinceWithStagedFlag(t *testing.T) {
+	for _, flag := range []string{"--staged", "--cached", "--unstaged"} {
+		t.Run(flag, func(t *testing.T) {
+			cmd := newSinceTestCommand(t, `{}`, "--since", "2", flag)
+			if _, err := sinceRefFromFlags(cmd); err == nil {
+				t.Errorf("--since with %s was accepted", flag)
+			}
+		})
+	}
+}
+
+func TestStagedDiffModeInConfigWithoutSince(t *testing.T) {
+	cmd := newSinceTestCommand(t, `{"diff_mode": "staged"}`)
+
+	ref, err := sinceRefFromFlags(cmd)
+	if err != nil || ref != "" {
+		t.Errorf("sinceRefFromFlags = %q, %v; want no ref", ref, err)
+	}
+	if !staged {
+		t.Error("diff_mode staged from the config was not applied")
+	}
+}
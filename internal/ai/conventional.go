@@ -0,0 +1,48 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxCommitSubjectLength is the longest subject line ValidateConventionalCommit accepts
+const MaxCommitSubjectLength = 72
+
+// conventionalSubjectRegex splits a subject into type, optional scope, breaking marker and description
+var conventionalSubjectRegex = regexp.MustCompile(`^([a-zA-Z]+)(\([^()]*\))?(!)?: (.*)$`)
+
+// ValidateConventionalCommit checks a commit message against the conventional commit format
+// and returns human-readable warnings. An empty result means the message looks fine.
+func ValidateConventionalCommit(msg string) []string {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return []string{"commit message is empty"}
+	}
+
+	var warnings []string
+	lines := strings.Split(msg, "\n")
+	subject := strings.TrimRight(lines[0], "\r")
+
+	if length := utf8.RuneCountInString(subject); length > MaxCommitSubjectLength {
+		warnings = append(warnings, fmt.Sprintf("subject is %d characters (max %d)", length, MaxCommitSubjectLength))
+	}
+
+	matches := conventionalSubjectRegex.FindStringSubmatch(subject)
+	switch {
+	case matches == nil:
+		warnings = append(warnings, `subject doesn't follow "type(scope): description"`)
+	case !slices.Contains(CommitTypes, matches[1]):
+		warnings = append(warnings, fmt.Sprintf("unknown commit type %q (expected one of: %s)", matches[1], strings.Join(CommitTypes, ", ")))
+	case strings.TrimSpace(matches[4]) == "":
+		warnings = append(warnings, "subject has no description after the type")
+	}
+
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		warnings = append(warnings, "missing blank line between subject and body")
+	}
+
+	return warnings
+}
@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// debugLogMu serializes writes to the debug log across concurrent requests
+var debugLogMu sync.Mutex
+
+// logExchange appends a prompt and its raw completion to config.DebugLog, if set.
+// Both are redacted with the configured patterns first, since the prompt also carries
+// text that never went through prepareDiffContent (commit subjects, branch logs).
+// Logging is best-effort and never fails the request.
+func (s *Service) logExchange(prompt, response string, started time.Time, reqErr error) {
+	if s.config.DebugLog == "" {
+		return
+	}
+	if s.config.RedactSecrets {
+		prompt, _ = RedactSecrets(prompt, s.config.RedactPatterns)
+		response, _ = RedactSecrets(response, s.config.RedactPatterns)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "=== %s %s (%s) ===\n", started.Format(time.RFC3339), s.config.Model, time.Since(started).Round(time.Millisecond))
+	b.WriteString("--- prompt ---\n")
+	b.WriteString(prompt)
+	b.WriteString("\n--- response ---\n")
+	b.WriteString(response)
+	b.WriteString("\n")
+	if reqErr != nil {
+		fmt.Fprintf(&b, "--- error ---\n%v\n", reqErr)
+	}
+	b.WriteString("\n")
+
+	debugLogMu.Lock()
+	defer debugLogMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.config.DebugLog), 0o700); err != nil {
+		return
+	}
+	file, err := os.OpenFile(s.config.DebugLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.WriteString(b.String())
+}
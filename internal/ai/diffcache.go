@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// maxCachedDiffs bounds the prepared diffs kept per service; the cache is cleared when full
+const maxCachedDiffs = 8
+
+// diffCacheKey identifies a prepared diff by the content it was built from and its format
+type diffCacheKey struct {
+	fingerprint uint64
+	numbered    bool // Built by prepareReviewDiffContent
+}
+
+// preparedDiff is a redacted prompt diff and the number of secrets redacted from it
+type preparedDiff struct {
+	content    string
+	redactions int
+}
+
+// cachedDiff returns the redacted diff built by format for files, reusing the result of an
+// earlier call with the same files so back-to-back AI actions in one session skip the
+// rebuild. Any change to the files (after a refresh or staging) yields a new fingerprint.
+func (s *Service) cachedDiff(files []parser.FileDiff, numbered bool, format func([]parser.FileDiff) string) string {
+	key := diffCacheKey{fingerprint: s.fingerprint(files), numbered: numbered}
+
+	s.mu.Lock()
+	if cached, ok := s.diffCache[key]; ok {
+		s.lastRedactions = cached.redactions
+		s.mu.Unlock()
+		return cached.content
+	}
+	s.mu.Unlock()
+
+	content, count := s.redactContent(format(files))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRedactions = count
+	if s.diffCache == nil || len(s.diffCache) >= maxCachedDiffs {
+		s.diffCache = make(map[diffCacheKey]preparedDiff)
+	}
+	s.diffCache[key] = preparedDiff{content: content, redactions: count}
+	return content
+}
+
+// maxFileHashes bounds the per-file fingerprints kept per service; cleared when full
+const maxFileHashes = 4096
+
+// fileIdentity identifies a parsed file diff without reading its lines. Parsing a diff
+// again, as a refresh does, allocates new hunks, so a changed diff never shares one.
+type fileIdentity struct {
+	path      string
+	hunks     *parser.Hunk
+	hunkCount int
+}
+
+// fingerprint returns fingerprintFiles(files), hashing each file's lines only the first
+// time that file diff is seen, so repeated AI actions on a loaded diff don't rehash it
+func (s *Service) fingerprint(files []parser.FileDiff) uint64 {
+	h := fnv.New64a()
+	var sum [8]byte
+	for _, file := range files {
+		var fileHash uint64
+		if len(file.Hunks) == 0 {
+			fileHash = fingerprintFile(file)
+		} else {
+			id := fileIdentity{path: file.NewPath, hunks: &file.Hunks[0], hunkCount: len(file.Hunks)}
+			s.mu.Lock()
+			cached, ok := s.fileHashes[id]
+			s.mu.Unlock()
+			if ok {
+				fileHash = cached
+			} else {
+				fileHash = fingerprintFile(file)
+				s.mu.Lock()
+				if s.fileHashes == nil || len(s.fileHashes) >= maxFileHashes {
+					s.fileHashes = make(map[fileIdentity]uint64)
+				}
+				s.fileHashes[id] = fileHash
+				s.mu.Unlock()
+			}
+		}
+		binary.LittleEndian.PutUint64(sum[:], fileHash)
+		h.Write(sum[:])
+	}
+	return h.Sum64()
+}
+
+// fingerprintFiles hashes everything prepared diffs are built from, file by file (see
+// fingerprintFile)
+func fingerprintFiles(files []parser.FileDiff) uint64 {
+	h := fnv.New64a()
+	var sum [8]byte
+	for _, file := range files {
+		binary.LittleEndian.PutUint64(sum[:], fingerprintFile(file))
+		h.Write(sum[:])
+	}
+	return h.Sum64()
+}
+
+// fingerprintFile hashes the file header, the hunk headers and every line's type and
+// content, so even a same-length edit yields a new fingerprint
+func fingerprintFile(file parser.FileDiff) uint64 {
+	h := fnv.New64a()
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	flag := func(b bool) string {
+		return strconv.FormatBool(b)
+	}
+
+	write(file.OldPath)
+	write(file.NewPath)
+	write(file.OldBlob + ".." + file.NewBlob + " " + file.OldMode + " " + file.Mode)
+	write(flag(file.IsNew) + flag(file.IsDeleted) + flag(file.IsRenamed) + flag(file.IsBinary))
+	for _, hunk := range file.Hunks {
+		write(strconv.Itoa(hunk.OldStart) + "," + strconv.Itoa(hunk.OldLines) + "," +
+			strconv.Itoa(hunk.NewStart) + "," + strconv.Itoa(hunk.NewLines))
+		for _, line := range hunk.Lines {
+			write(strconv.Itoa(int(line.Type)))
+			write(line.Content)
+		}
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// fingerprintTestFile returns an added file with the given new blob ID and lines
+func fingerprintTestFile(blob string, lines ...string) parser.FileDiff {
+	hunk := parser.Hunk{OldStart: 1, OldLines: 0, NewStart: 1, NewLines: len(lines)}
+	for i, content := range lines {
+		hunk.Lines = append(hunk.Lines, parser.Line{Type: parser.LineAdded, Content: content, NewLineNum: i + 1})
+	}
+	return parser.FileDiff{OldPath: "f.go", NewPath: "f.go", OldBlob: "0000000", NewBlob: blob, Hunks: []parser.Hunk{hunk}}
+}
+
+// TestFingerprintFiles checks that the fingerprint changes with any change to the content,
+// including same-length edits that keep the blob IDs
+func TestFingerprintFiles(t *testing.T) {
+	tracked := fingerprintTestFile("1111111", "package a")
+	untracked := fingerprintTestFile("0000000", "package a")
+
+	tests := []struct {
+		name string
+		a, b parser.FileDiff
+		same bool
+	}{
+		{"identical", tracked, fingerprintTestFile("1111111", "package a"), true},
+		{"new blob", tracked, fingerprintTestFile("2222222", "package a"), false},
+		{"longer line", tracked, fingerprintTestFile("1111111", "package ab"), false},
+		{"more lines", tracked, fingerprintTestFile("1111111", "package a", ""), false},
+		{"same length edit", tracked, fingerprintTestFile("1111111", "package b"), false},
+		{"untracked identical", untracked, fingerprintTestFile("0000000", "package a"), true},
+		{"untracked same length edit", untracked, fingerprintTestFile("0000000", "package b"), false},
+	}
+	for _, tt := range tests {
+		a := fingerprintFiles([]parser.FileDiff{tt.a})
+		b := fingerprintFiles([]parser.FileDiff{tt.b})
+		if (a == b) != tt.same {
+			t.Errorf("%s: fingerprints equal = %v, want %v", tt.name, a == b, tt.same)
+		}
+	}
+}
+
+// TestCachedDiffRedactions checks that a cache hit reports the redactions of the prompt
+// it returns
+func TestCachedDiffRedactions(t *testing.T) {
+	patterns, err := CompileRedactPatterns(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServiceWithClient(&Config{RedactSecrets: true, RedactPatterns: patterns}, nil)
+	format := func(files []parser.FileDiff) string {
+		return files[0].Hunks[0].Lines[0].Content
+	}
+	secret := []parser.FileDiff{fingerprintTestFile("1111111", `token = "abcdef123456"`)}
+	clean := []parser.FileDiff{fingerprintTestFile("1111111", `value = "abcdef123456"`)}
+
+	s.cachedDiff(secret, false, format)
+	s.cachedDiff(clean, false, format)
+	if got := s.LastRedactions(); got != 0 {
+		t.Fatalf("LastRedactions() = %d after a clean diff, want 0", got)
+	}
+	if got := s.cachedDiff(secret, false, format); got != `token = "`+RedactedValue+`"` {
+		t.Errorf("cached diff = %q", got)
+	}
+	if got := s.LastRedactions(); got != 1 {
+		t.Errorf("LastRedactions() = %d after a cache hit, want 1", got)
+	}
+}
+
+// TestServiceFingerprint checks that the memoized fingerprint matches fingerprintFiles and
+// that a reloaded diff with a same-length edit gets a new one
+func TestServiceFingerprint(t *testing.T) {
+	s := NewServiceWithClient(&Config{}, nil)
+	files := []parser.FileDiff{fingerprintTestFile("1111111", "package a"), {OldPath: "b.bin", NewPath: "b.bin", IsBinary: true}}
+
+	first := s.fingerprint(files)
+	if want := fingerprintFiles(files); first != want {
+		t.Errorf("fingerprint = %x, want %x", first, want)
+	}
+	if again := s.fingerprint(files); again != first {
+		t.Errorf("fingerprint changed for the same files: %x, then %x", first, again)
+	}
+
+	reloaded := []parser.FileDiff{fingerprintTestFile("1111111", "package b"), files[1]}
+	if s.fingerprint(reloaded) == first {
+		t.Error("reloaded diff with an edit kept the fingerprint")
+	}
+}
@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Errors returned (wrapped) by the AI layer, so callers can tell failures apart with
+// errors.Is and suggest a fix
+var (
+	// ErrNoAPIKey means the API key is missing or was rejected
+	ErrNoAPIKey = errors.New("missing or invalid API key")
+	// ErrRateLimited means the provider is throttling requests; retrying later may work
+	ErrRateLimited = errors.New("rate limited by the AI provider")
+	// ErrContextLengthExceeded means the prompt, mostly the diff, is too long for the model
+	ErrContextLengthExceeded = errors.New("diff too large for the model's context window")
+	// ErrInvalidResponse means the model's reply couldn't be parsed
+	ErrInvalidResponse = errors.New("malformed AI response")
+	// ErrProvider is any other error reported by the provider or on the way to it
+	ErrProvider = errors.New("AI provider error")
+)
+
+// classifyOpenAIError wraps an error from the OpenAI client in the matching AI layer
+// error, keeping the original for its message. Context errors are returned unchanged.
+func classifyOpenAIError(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		code, _ := apiErr.Code.(string)
+		switch {
+		case code == "context_length_exceeded" || strings.Contains(apiErr.Message, "maximum context length"):
+			return fmt.Errorf("%w: %w", ErrContextLengthExceeded, err)
+		case apiErr.HTTPStatusCode == http.StatusUnauthorized || code == "invalid_api_key":
+			return fmt.Errorf("%w: %w", ErrNoAPIKey, err)
+		case apiErr.HTTPStatusCode == http.StatusTooManyRequests && code != "insufficient_quota":
+			return fmt.Errorf("%w: %w", ErrRateLimited, err)
+		}
+		return fmt.Errorf("%w: %w", ErrProvider, err)
+	}
+
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.HTTPStatusCode {
+		case http.StatusUnauthorized:
+			return fmt.Errorf("%w: %w", ErrNoAPIKey, err)
+		case http.StatusTooManyRequests:
+			return fmt.Errorf("%w: %w", ErrRateLimited, err)
+		}
+	}
+	return fmt.Errorf("%w: %w", ErrProvider, err)
+}
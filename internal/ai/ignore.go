@@ -0,0 +1,219 @@
+package ai
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// IgnoreFileName is the file at the repository root listing paths never sent to the AI
+const IgnoreFileName = ".criticaignore"
+
+// IgnoreRules holds the patterns of an ignore file in gitignore syntax
+type IgnoreRules struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// LoadIgnoreFile reads IgnoreFileName from dir. A missing file yields nil rules and no error.
+func LoadIgnoreFile(dir string) (*IgnoreRules, error) {
+	file, err := os.Open(filepath.Join(dir, IgnoreFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", IgnoreFileName, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", IgnoreFileName, err)
+	}
+
+	return ParseIgnoreRules(lines), nil
+}
+
+// ParseIgnoreRules compiles gitignore-style pattern lines. Blank lines and comments are skipped.
+func ParseIgnoreRules(lines []string) *IgnoreRules {
+	rules := &IgnoreRules{}
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			// "\#" and "\!" match a literal leading character
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// A slash anywhere but the end anchors the pattern to the repository root
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		expr := ignorePatternRegex(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		regex, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			continue
+		}
+		rule.regex = regex
+		rules.rules = append(rules.rules, rule)
+	}
+	return rules
+}
+
+// ignorePatternRegex translates gitignore wildcards into a regular expression
+func ignorePatternRegex(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**/") {
+				b.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(pattern[i:], "**") {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end == -1 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether a repository-relative path is ignored. Later rules override earlier
+// ones, and a path is ignored when any of its parent directories is.
+func (r *IgnoreRules) Match(path string) bool {
+	if r == nil || len(r.rules) == 0 {
+		return false
+	}
+
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	ignored := false
+	for _, rule := range r.rules {
+		if rule.matches(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (rule ignoreRule) matches(path string) bool {
+	if !rule.dirOnly && rule.regex.MatchString(path) {
+		return true
+	}
+	// Check every parent directory
+	for dir := path; ; {
+		idx := strings.LastIndex(dir, "/")
+		if idx == -1 {
+			return false
+		}
+		dir = dir[:idx]
+		if rule.regex.MatchString(dir) {
+			return true
+		}
+	}
+}
+
+// matchesFile reports whether either side of a file diff is ignored
+func (r *IgnoreRules) matchesFile(file parser.FileDiff) bool {
+	return r.Match(file.NewPath) || (file.OldPath != "" && r.Match(file.OldPath))
+}
+
+// WithheldFiles returns the paths of files excluded from AI prompts by the ignore file
+func (s *Service) WithheldFiles(files []parser.FileDiff) []string {
+	var withheld []string
+	for _, file := range files {
+		if s.config.Ignore.matchesFile(file) {
+			withheld = append(withheld, file.NewPath)
+		}
+	}
+	return withheld
+}
+
+// contextFiles drops files matched by the ignore file so they are never sent to the AI,
+// then keeps the MaxFiles with the most changed lines
+func (s *Service) contextFiles(files []parser.FileDiff) []parser.FileDiff {
+	return parser.KeepLargest(s.allowedFiles(files), s.config.MaxFiles)
+}
+
+// allowedFiles drops files matched by the ignore file
+func (s *Service) allowedFiles(files []parser.FileDiff) []parser.FileDiff {
+	if s.config.Ignore == nil {
+		return files
+	}
+
+	result := make([]parser.FileDiff, 0, len(files))
+	for _, file := range files {
+		if !s.config.Ignore.matchesFile(file) {
+			result = append(result, file)
+		}
+	}
+	return result
+}
+
+// OmittedFileCount returns how many files beyond MaxFiles are left out of AI prompts
+func (s *Service) OmittedFileCount(files []parser.FileDiff) int {
+	allowed := len(s.allowedFiles(files))
+	if s.config.MaxFiles <= 0 || allowed <= s.config.MaxFiles {
+		return 0
+	}
+	return allowed - s.config.MaxFiles
+}
+
+// omittedNote tells the AI about changed files left out of the prompt by MaxFiles
+func (s *Service) omittedNote(files []parser.FileDiff) string {
+	n := s.OmittedFileCount(files)
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%d more changed files with fewer changes are not included)\n", n)
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// Provider sends chat requests to a model API. The Service builds the requests;
+// implementations only transport them, so tests can substitute canned responses.
+type Provider interface {
+	// Complete sends a chat request and returns the content of the first choice
+	Complete(ctx context.Context, req openai.ChatCompletionRequest) (string, error)
+	// Stream sends a streaming chat request, calling onDelta with each piece of content
+	// as it arrives, and returns once the response is complete
+	Stream(ctx context.Context, req openai.ChatCompletionRequest, onDelta func(string)) error
+}
+
+// openAIProvider is the Provider for the OpenAI API and OpenAI-compatible endpoints
+type openAIProvider struct {
+	client *openai.Client
+}
+
+// NewOpenAIProvider returns a Provider for the OpenAI API, or the compatible endpoint at
+// config.BaseURL. httpClient carries the requests; when nil, a client honoring the proxy
+// environment variables and config.RootCAs is used.
+func NewOpenAIProvider(config *Config, httpClient *http.Client) Provider {
+	if config.APIKey == "" && config.BaseURL == "" {
+		return noAPIKeyProvider{}
+	}
+
+	clientConfig := openai.DefaultConfig(config.APIKey)
+	if config.BaseURL != "" {
+		clientConfig.BaseURL = config.BaseURL
+	}
+	if httpClient == nil {
+		httpClient = newHTTPClient(config)
+	}
+	clientConfig.HTTPClient = httpClient
+
+	return &openAIProvider{client: openai.NewClientWithConfig(clientConfig)}
+}
+
+// Complete sends a chat request and returns the content of the first choice
+func (p *openAIProvider) Complete(ctx context.Context, req openai.ChatCompletionRequest) (string, error) {
+	resp, err := p.client.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return "", classifyOpenAIError(err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("%w: no response from AI service", ErrInvalidResponse)
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// noAPIKeyProvider fails every request with ErrNoAPIKey, for the OpenAI API without a key
+type noAPIKeyProvider struct{}
+
+func (noAPIKeyProvider) Complete(context.Context, openai.ChatCompletionRequest) (string, error) {
+	return "", ErrNoAPIKey
+}
+
+func (noAPIKeyProvider) Stream(context.Context, openai.ChatCompletionRequest, func(string)) error {
+	return ErrNoAPIKey
+}
+
+// Stream sends a streaming chat request, calling onDelta with each piece of content
+func (p *openAIProvider) Stream(ctx context.Context, req openai.ChatCompletionRequest, onDelta func(string)) error {
+	stream, err := p.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return classifyOpenAIError(err)
+	}
+	defer stream.Close()
+
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return classifyOpenAIError(err)
+		}
+
+		if len(response.Choices) > 0 && response.Choices[0].Delta.Content != "" {
+			onDelta(response.Choices[0].Delta.Content)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RedactedValue replaces secrets in prompts sent to the AI
+const RedactedValue = "***REDACTED***"
+
+// DefaultRedactPatterns match common credentials. When a pattern has capture groups only
+// the last group is replaced, so "password = hunter2" keeps its key.
+var DefaultRedactPatterns = []string{
+	`(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|client[_-]?secret|auth)["']?\s*[:=]\s*["']?([^\s"',;]{4,})`,
+	`(?i)bearer\s+([A-Za-z0-9._~+/-]{16,}=*)`,
+	`AKIA[0-9A-Z]{16}`,
+	`gh[pousr]_[A-Za-z0-9]{36,}`,
+	`github_pat_[A-Za-z0-9_]{22,}`,
+	`sk-[A-Za-z0-9_-]{20,}`,
+	`xox[abprs]-[A-Za-z0-9-]{10,}`,
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----`,
+}
+
+// CompileRedactPatterns compiles DefaultRedactPatterns followed by extra user patterns
+func CompileRedactPatterns(extra []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(DefaultRedactPatterns)+len(extra))
+	for _, expr := range append(append([]string{}, DefaultRedactPatterns...), extra...) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", expr, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// RedactSecrets replaces every match of patterns in content with RedactedValue and
+// returns the redacted content and the number of replacements
+func RedactSecrets(content string, patterns []*regexp.Regexp) (string, int) {
+	count := 0
+	for _, re := range patterns {
+		content = re.ReplaceAllStringFunc(content, func(match string) string {
+			groups := re.FindStringSubmatchIndex(match)
+			start, end := 0, len(match)
+			if n := len(groups) / 2; n > 1 && groups[2*(n-1)] >= 0 {
+				start, end = groups[2*(n-1)], groups[2*(n-1)+1]
+			}
+			if match[start:end] == RedactedValue {
+				return match
+			}
+			count++
+			return match[:start] + RedactedValue + match[end:]
+		})
+	}
+	return content, count
+}
+
+// redact applies the configured redaction to prompt content and records the count
+// for LastRedactions
+func (s *Service) redact(content string) string {
+	content, count := s.redactContent(content)
+
+	s.mu.Lock()
+	s.lastRedactions = count
+	s.mu.Unlock()
+
+	return content
+}
+
+// redactContent applies the configured redaction to prompt content without recording
+// the count, for callers that record it themselves
+func (s *Service) redactContent(content string) (string, int) {
+	if !s.config.RedactSecrets {
+		return content, 0
+	}
+	return RedactSecrets(content, s.config.RedactPatterns)
+}
+
+// LastRedactions returns how many secrets were redacted from the most recent prompt
+func (s *Service) LastRedactions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRedactions
+}
@@ -3,11 +3,18 @@ package ai
 import (
 	"bufio"
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/danielss-dev/critica/internal/parser"
 	"github.com/sashabaranov/go-openai"
@@ -16,8 +23,15 @@ import (
 
 // Service handles AI operations for code analysis
 type Service struct {
-	client *openai.Client
-	config *Config
+	provider Provider
+	config   *Config
+
+	mu                 sync.Mutex
+	lastRedactions     int
+	lastStreamFallback bool
+	lastRawResponse    string
+	diffCache          map[diffCacheKey]preparedDiff // Prepared diffs, see cachedDiff
+	fileHashes         map[fileIdentity]uint64       // Per-file fingerprints, see fingerprint
 }
 
 // Config holds AI service configuration
@@ -26,6 +40,28 @@ type Config struct {
 	Model               string
 	MaxCompletionTokens int
 	BaseURL             string
+	// RootCAs, when set, are the roots trusted for TLS to the API (see LoadCACerts)
+	RootCAs *x509.CertPool
+	// Ignore excludes matching files from every prompt (see IgnoreFileName)
+	Ignore *IgnoreRules
+	// RedactSecrets replaces values matching RedactPatterns in prompts with RedactedValue
+	RedactSecrets  bool
+	RedactPatterns []*regexp.Regexp
+	// JSONResponseFormat requests response_format json_object for the analysis, which not
+	// every model or OpenAI-compatible provider supports
+	JSONResponseFormat bool
+	// AnalysisIncludesCommitAndPR also asks the analysis for a commit message and PR
+	// description, which otherwise come from the dedicated commands
+	AnalysisIncludesCommitAndPR bool
+	// Language is the human language responses are written in; "" leaves it to the model
+	Language string
+	// DebugLog is a file every prompt and raw response are appended to, or "" to disable
+	DebugLog string
+	// StreamOutput receives streamed responses as they arrive when it is a terminal; nil
+	// keeps them quiet, as the interactive UI needs while it owns the terminal
+	StreamOutput io.Writer
+	// MaxFiles caps the files in a prompt, keeping those with the most changed lines; 0 sends all
+	MaxFiles int
 }
 
 // AnalysisResult contains the AI analysis results
@@ -41,19 +77,51 @@ type AnalysisResult struct {
 	PerformanceNotes []string `json:"performance_notes"`
 }
 
-// NewService creates a new AI service instance
+// ReviewComment is a single review finding anchored to a line in the diff
+type ReviewComment struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Comment  string `json:"comment"`
+	// HunkIndex and LineIndex locate the comment within the matching FileDiff
+	HunkIndex int `json:"-"`
+	LineIndex int `json:"-"`
+}
+
+// NewService creates a new AI service instance talking to the OpenAI API, or the
+// compatible endpoint at config.BaseURL
 func NewService(config *Config) *Service {
-	clientConfig := openai.DefaultConfig(config.APIKey)
-	if config.BaseURL != "" {
-		clientConfig.BaseURL = config.BaseURL
+	return NewServiceWithClient(config, NewOpenAIProvider(config, nil))
+}
+
+// NewServiceWithClient creates an AI service that sends its requests through provider,
+// e.g. an OpenAI provider with a custom http.Client, or a fake in tests
+func NewServiceWithClient(config *Config, provider Provider) *Service {
+	return &Service{
+		provider: provider,
+		config:   config,
 	}
+}
 
-	client := openai.NewClientWithConfig(clientConfig)
+// ModelName returns the model requests are sent to
+func (s *Service) ModelName() string {
+	return s.config.Model
+}
 
-	return &Service{
-		client: client,
-		config: config,
+// ProviderName returns a short name for the API endpoint: "OpenAI" by default,
+// otherwise the host of the configured base URL
+func (s *Service) ProviderName() string {
+	if s.config.BaseURL == "" {
+		return "OpenAI"
 	}
+	u, err := url.Parse(s.config.BaseURL)
+	if err != nil || u.Host == "" {
+		return s.config.BaseURL
+	}
+	if u.Hostname() == "api.openai.com" {
+		return "OpenAI"
+	}
+	return u.Host
 }
 
 // LoadConfig loads AI configuration from environment variables and config file
@@ -81,7 +149,7 @@ func (s *Service) AnalyzeDiff(ctx context.Context, files []parser.FileDiff) (*An
 	prompt := s.buildAnalysisPrompt(diffContent)
 
 	// Call the AI service with quiet streaming (don't display raw JSON)
-	response, err := s.callAIStreamQuiet(ctx, prompt)
+	response, err := s.callAIStreamJSON(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("AI analysis failed: %w", err)
 	}
@@ -95,21 +163,142 @@ func (s *Service) AnalyzeDiff(ctx context.Context, files []parser.FileDiff) (*An
 	return result, nil
 }
 
+// CommitTypes lists the conventional commit types the commit prompt allows
+var CommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "chore", "build", "ci", "revert"}
+
+// CommitOptions constrains a generated commit message. Empty fields leave the choice to the model.
+type CommitOptions struct {
+	Type  string
+	Scope string
+	// RecentSubjects are existing commit subjects from the repository whose style the message should follow
+	RecentSubjects []string
+}
+
+// prefix returns the required "type(scope): " subject prefix, or "" if no type was chosen
+func (o CommitOptions) prefix() string {
+	if o.Type == "" {
+		return ""
+	}
+	if o.Scope != "" {
+		return fmt.Sprintf("%s(%s): ", o.Type, o.Scope)
+	}
+	return o.Type + ": "
+}
+
+// styleExamples returns the prompt section listing recent commit subjects, or "" if there are none
+func (o CommitOptions) styleExamples() string {
+	if len(o.RecentSubjects) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nRecent commit messages in this repository (match their style, tone and level of detail):\n")
+	for _, subject := range o.RecentSubjects {
+		b.WriteString("- " + subject + "\n")
+	}
+	return b.String()
+}
+
 // GenerateCommitMessage generates a commit message based on the changes
 func (s *Service) GenerateCommitMessage(ctx context.Context, files []parser.FileDiff) (string, error) {
+	return s.GenerateCommitMessageWithOptions(ctx, files, CommitOptions{})
+}
+
+// GenerateCommitMessageWithOptions generates a commit message whose subject starts with
+// the type and scope given in opts
+func (s *Service) GenerateCommitMessageWithOptions(ctx context.Context, files []parser.FileDiff, opts CommitOptions) (string, error) {
 	if len(files) == 0 {
 		return "No changes to commit", nil
 	}
 
 	diffContent := s.prepareDiffContent(files)
-	prompt := s.buildCommitMessagePrompt(diffContent)
+	prompt := s.buildCommitMessagePrompt(diffContent, opts)
 
-	response, err := s.callAIStream(ctx, prompt, os.Stdout)
+	response, err := s.callAIStream(ctx, prompt)
 	if err != nil {
 		return "", fmt.Errorf("commit message generation failed: %w", err)
 	}
 
-	return strings.TrimSpace(response), nil
+	return enforceCommitPrefix(strings.TrimSpace(response), opts), nil
+}
+
+// GenerateCommitMessages generates n alternative commit messages to choose from
+func (s *Service) GenerateCommitMessages(ctx context.Context, files []parser.FileDiff, n int) ([]string, error) {
+	return s.GenerateCommitMessagesWithOptions(ctx, files, n, CommitOptions{})
+}
+
+// GenerateCommitMessagesWithOptions generates n alternative commit messages in a single
+// request, each starting with the type and scope given in opts
+func (s *Service) GenerateCommitMessagesWithOptions(ctx context.Context, files []parser.FileDiff, n int, opts CommitOptions) ([]string, error) {
+	if len(files) == 0 {
+		return []string{"No changes to commit"}, nil
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	diffContent := s.prepareDiffContent(files)
+	prompt := s.buildCommitMessagesPrompt(diffContent, opts, n)
+
+	response, err := s.callAIStreamQuiet(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("commit message generation failed: %w", err)
+	}
+
+	candidates, err := parseCommitCandidates(response)
+	if err != nil {
+		// Fall back to treating the whole response as a single message
+		candidates = []string{strings.TrimSpace(response)}
+	}
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	for i, candidate := range candidates {
+		candidates[i] = enforceCommitPrefix(candidate, opts)
+	}
+
+	return candidates, nil
+}
+
+// parseCommitCandidates extracts the JSON array of commit messages from the AI response
+func parseCommitCandidates(response string) ([]string, error) {
+	cleanedResponse := strings.TrimSpace(response)
+
+	startIdx := strings.Index(cleanedResponse, "[")
+	endIdx := strings.LastIndex(cleanedResponse, "]")
+	if startIdx == -1 || endIdx == -1 || startIdx >= endIdx {
+		return nil, fmt.Errorf("%w: no JSON array found in response", ErrInvalidResponse)
+	}
+
+	var messages []string
+	if err := json.Unmarshal([]byte(cleanedResponse[startIdx:endIdx+1]), &messages); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	candidates := cleanStringArray(messages)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: no commit messages in response", ErrInvalidResponse)
+	}
+	return candidates, nil
+}
+
+// conventionalPrefixRegex matches a conventional commit subject prefix such as "feat(ui)!: "
+var conventionalPrefixRegex = regexp.MustCompile(`^[a-zA-Z]+(\([^)]*\))?!?:\s*`)
+
+// enforceCommitPrefix makes sure the subject line starts with the chosen type and scope,
+// replacing any prefix the model picked on its own
+func enforceCommitPrefix(message string, opts CommitOptions) string {
+	prefix := opts.prefix()
+	if prefix == "" || strings.HasPrefix(message, prefix) {
+		return message
+	}
+
+	subject, body, hasBody := strings.Cut(message, "\n")
+	subject = prefix + conventionalPrefixRegex.ReplaceAllString(subject, "")
+	if hasBody {
+		return subject + "\n" + body
+	}
+	return subject
 }
 
 // GeneratePRDescription generates a PR description based on the changes
@@ -121,7 +310,7 @@ func (s *Service) GeneratePRDescription(ctx context.Context, files []parser.File
 	diffContent := s.prepareDiffContent(files)
 	prompt := s.buildPRDescriptionPrompt(diffContent)
 
-	response, err := s.callAIStream(ctx, prompt, os.Stdout)
+	response, err := s.callAIStream(ctx, prompt)
 	if err != nil {
 		return "", fmt.Errorf("PR description generation failed: %w", err)
 	}
@@ -129,15 +318,24 @@ func (s *Service) GeneratePRDescription(ctx context.Context, files []parser.File
 	return strings.TrimSpace(response), nil
 }
 
-// GeneratePRDescriptionWithBranches generates a PR description based on branch diff
-func (s *Service) GeneratePRDescriptionWithBranches(ctx context.Context, diffContent, sourceBranch, targetBranch string) (string, error) {
+// GeneratePRDescriptionWithBranches generates a PR description based on branch diff.
+// commitSummary describes the branch's commits (count, authors, dates) and may be empty.
+func (s *Service) GeneratePRDescriptionWithBranches(ctx context.Context, diffContent, sourceBranch, targetBranch, commitSummary string) (string, error) {
 	if diffContent == "" {
 		return "No changes to describe", nil
 	}
 
-	prompt := s.buildPRDescriptionPromptWithBranches(diffContent, sourceBranch, targetBranch)
+	// Re-serialize the diff like the other prompts, so ignored files are left out and
+	// ai_max_files applies
+	files, err := parser.ParseDiff(diffContent)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse branch diff: %w", err)
+	}
+	diffContent = s.prepareDiffContent(files)
+
+	prompt := s.buildPRDescriptionPromptWithBranches(diffContent, sourceBranch, targetBranch, commitSummary)
 
-	response, err := s.callAIStream(ctx, prompt, os.Stdout)
+	response, err := s.callAIStream(ctx, prompt)
 	if err != nil {
 		return "", fmt.Errorf("PR description generation failed: %w", err)
 	}
@@ -151,10 +349,10 @@ func (s *Service) SuggestImprovements(ctx context.Context, files []parser.FileDi
 		return []string{}, nil
 	}
 
-	diffContent := s.prepareDiffContent(files)
+	diffContent := s.prepareReviewDiffContent(files)
 	prompt := s.buildImprovementsPrompt(diffContent)
 
-	response, err := s.callAIStream(ctx, prompt, os.Stdout)
+	response, err := s.callAIStream(ctx, prompt)
 	if err != nil {
 		return nil, fmt.Errorf("improvement suggestions failed: %w", err)
 	}
@@ -181,7 +379,7 @@ func (s *Service) ExplainChanges(ctx context.Context, files []parser.FileDiff) (
 	diffContent := s.prepareDiffContent(files)
 	prompt := s.buildExplanationPrompt(diffContent)
 
-	response, err := s.callAIStream(ctx, prompt, os.Stdout)
+	response, err := s.callAIStream(ctx, prompt)
 	if err != nil {
 		return "", fmt.Errorf("change explanation failed: %w", err)
 	}
@@ -189,11 +387,56 @@ func (s *Service) ExplainChanges(ctx context.Context, files []parser.FileDiff) (
 	return strings.TrimSpace(response), nil
 }
 
+// Summarize returns a single short paragraph describing the changes, for standups and
+// quick overviews. It is much cheaper than AnalyzeDiff.
+func (s *Service) Summarize(ctx context.Context, files []parser.FileDiff) (string, error) {
+	if len(files) == 0 {
+		return "No changes to summarize", nil
+	}
+
+	diffContent := s.prepareDiffContent(files)
+	prompt := s.buildSummaryPrompt(diffContent)
+
+	response, err := s.callAIStreamQuiet(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("summary failed: %w", err)
+	}
+
+	return strings.TrimSpace(response), nil
+}
+
+// ReviewDiff asks the AI for line-anchored review comments and maps them back to diff positions
+func (s *Service) ReviewDiff(ctx context.Context, files []parser.FileDiff) ([]ReviewComment, error) {
+	if len(files) == 0 {
+		return []ReviewComment{}, nil
+	}
+
+	diffContent := s.prepareReviewDiffContent(files)
+	prompt := s.buildReviewPrompt(diffContent)
+
+	response, err := s.callAIStreamQuiet(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("AI review failed: %w", err)
+	}
+
+	comments, err := parseReviewResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AI review: %w", err)
+	}
+
+	return anchorReviewComments(files, comments), nil
+}
+
 // prepareDiffContent converts FileDiff objects to a readable diff format
 func (s *Service) prepareDiffContent(files []parser.FileDiff) string {
+	return s.cachedDiff(files, false, s.formatDiffContent)
+}
+
+// formatDiffContent builds the diff text for prepareDiffContent
+func (s *Service) formatDiffContent(files []parser.FileDiff) string {
 	var content strings.Builder
 
-	for _, file := range files {
+	for _, file := range s.contextFiles(files) {
 		content.WriteString(fmt.Sprintf("File: %s\n", file.NewPath))
 		if file.IsNew {
 			content.WriteString("Status: New file\n")
@@ -226,12 +469,55 @@ func (s *Service) prepareDiffContent(files []parser.FileDiff) string {
 		}
 		content.WriteString("\n")
 	}
+	content.WriteString(s.omittedNote(files))
+
+	return content.String()
+}
+
+// prepareReviewDiffContent renders the diff with explicit line numbers so the AI can reference them
+func (s *Service) prepareReviewDiffContent(files []parser.FileDiff) string {
+	return s.cachedDiff(files, true, s.formatReviewDiffContent)
+}
+
+// formatReviewDiffContent builds the numbered diff text for prepareReviewDiffContent
+func (s *Service) formatReviewDiffContent(files []parser.FileDiff) string {
+	var content strings.Builder
+
+	for _, file := range s.contextFiles(files) {
+		content.WriteString(fmt.Sprintf("File: %s\n", file.NewPath))
+
+		for _, hunk := range file.Hunks {
+			content.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n",
+				hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines))
+
+			for _, line := range hunk.Lines {
+				switch line.Type {
+				case parser.LineAdded:
+					content.WriteString(fmt.Sprintf("%5d + %s\n", line.NewLineNum, line.Content))
+				case parser.LineDeleted:
+					content.WriteString(fmt.Sprintf("%5d - %s\n", line.OldLineNum, line.Content))
+				default:
+					content.WriteString(fmt.Sprintf("%5d   %s\n", line.NewLineNum, line.Content))
+				}
+			}
+			content.WriteString("\n")
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString(s.omittedNote(files))
 
 	return content.String()
 }
 
 // buildAnalysisPrompt creates a comprehensive analysis prompt
 func (s *Service) buildAnalysisPrompt(diffContent string) string {
+	commitAndPRFields := ""
+	if s.config.AnalysisIncludesCommitAndPR {
+		commitAndPRFields = `- commit_message: plain text string (conventional commit format)
+- pr_description: plain text string (multi-line description)
+`
+	}
+
 	return fmt.Sprintf(`Analyze the following git diff and provide a comprehensive analysis in JSON format.
 
 IMPORTANT: Return ONLY a single JSON object with these exact fields:
@@ -239,9 +525,7 @@ IMPORTANT: Return ONLY a single JSON object with these exact fields:
 - improvements: array of strings (improvement suggestions)
 - issues: array of strings (potential problems)
 - explanations: array of strings (explanations of changes)
-- commit_message: plain text string (conventional commit format)
-- pr_description: plain text string (multi-line description)
-- code_quality: plain text string (quality assessment)
+%s- code_quality: plain text string (quality assessment)
 - security_notes: array of strings (security observations)
 - performance_notes: array of strings (performance observations)
 
@@ -256,11 +540,16 @@ Focus your analysis on:
 Git diff:
 %s
 
-RESPOND ONLY WITH VALID JSON - no markdown, no code blocks, no extra text. Each string field must be plain text, never JSON.`, diffContent)
+RESPOND ONLY WITH VALID JSON - no markdown, no code blocks, no extra text. Each string field must be plain text, never JSON.`, commitAndPRFields, diffContent)
 }
 
 // buildCommitMessagePrompt creates a prompt for commit message generation
-func (s *Service) buildCommitMessagePrompt(diffContent string) string {
+func (s *Service) buildCommitMessagePrompt(diffContent string, opts CommitOptions) string {
+	constraint := ""
+	if prefix := opts.prefix(); prefix != "" {
+		constraint = fmt.Sprintf("\nThe subject line MUST start with exactly %q.\n", prefix)
+	}
+
 	return fmt.Sprintf(`Generate a conventional commit message for the following git diff. Use the format:
 <type>[optional scope]: <description>
 
@@ -268,12 +557,37 @@ func (s *Service) buildCommitMessagePrompt(diffContent string) string {
 
 [optional footer(s)]
 
-Types: feat, fix, docs, style, refactor, perf, test, chore, build, ci, revert
+Types: %s
+%s%s
+Git diff:
+%s
+
+Respond only with the commit message, no additional text.`, strings.Join(CommitTypes, ", "), constraint, opts.styleExamples(), diffContent)
+}
+
+// buildCommitMessagesPrompt creates a prompt asking for n alternative commit messages
+func (s *Service) buildCommitMessagesPrompt(diffContent string, opts CommitOptions, n int) string {
+	constraint := ""
+	if prefix := opts.prefix(); prefix != "" {
+		constraint = fmt.Sprintf("\nEvery subject line MUST start with exactly %q.\n", prefix)
+	}
+
+	return fmt.Sprintf(`Generate %d alternative conventional commit messages for the following git diff. Each message uses the format:
+<type>[optional scope]: <description>
+
+[optional body]
+
+[optional footer(s)]
+
+Types: %s
+%s%s
+Vary the style and length: start with a short subject-only message, then progressively more detailed messages with a body explaining what changed and why.
 
 Git diff:
 %s
 
-Respond only with the commit message, no additional text.`, diffContent)
+Return ONLY a JSON array of %d strings, one commit message per element. Use \n for line breaks inside a message.
+RESPOND ONLY WITH VALID JSON - no markdown, no code blocks, no extra text.`, n, strings.Join(CommitTypes, ", "), constraint, opts.styleExamples(), diffContent, n)
 }
 
 // buildPRDescriptionPrompt creates a prompt for PR description generation
@@ -293,7 +607,12 @@ Respond with a well-formatted PR description.`, diffContent)
 }
 
 // buildPRDescriptionPromptWithBranches creates a prompt for PR description generation with branch context
-func (s *Service) buildPRDescriptionPromptWithBranches(diffContent, sourceBranch, targetBranch string) string {
+func (s *Service) buildPRDescriptionPromptWithBranches(diffContent, sourceBranch, targetBranch, commitSummary string) string {
+	commits := ""
+	if commitSummary != "" {
+		commits = fmt.Sprintf("\nCommits on %s that are not on %s: %s. Use this to describe the scope of the work accurately.\n", sourceBranch, targetBranch, commitSummary)
+	}
+
 	return fmt.Sprintf(`Generate a comprehensive PR description for a pull request from branch "%s" to "%s". Include:
 
 1. Summary of changes
@@ -302,11 +621,11 @@ func (s *Service) buildPRDescriptionPromptWithBranches(diffContent, sourceBranch
 4. Breaking changes (if any)
 5. Screenshots or examples (if applicable)
 6. Branch context and merge considerations
-
+%s
 Git diff from %s to %s:
 %s
 
-Respond with a well-formatted PR description that includes the branch context.`, sourceBranch, targetBranch, sourceBranch, targetBranch, diffContent)
+Respond with a well-formatted PR description that includes the branch context.`, sourceBranch, targetBranch, commits, sourceBranch, targetBranch, diffContent)
 }
 
 // buildImprovementsPrompt creates a prompt for improvement suggestions
@@ -320,10 +639,49 @@ func (s *Service) buildImprovementsPrompt(diffContent string) string {
 5. Error handling
 6. Documentation needs
 
+Each diff line is prefixed with its line number. For added and unchanged lines this is the line number in the new file; for deleted lines (marked with -) it is the line number in the old file.
+
 Git diff:
 %s
 
-Provide each suggestion as a separate line, starting with a brief description.`, diffContent)
+Provide each suggestion as a separate line, starting with a brief description. When a suggestion is about a specific line, start it with the file path and line number followed by " - ", for example "internal/app/server.go:42 - Check the error returned by Close".`, diffContent)
+}
+
+// improvementLocationRegex matches the "path:line - " prefix of a suggestion about a specific line
+var improvementLocationRegex = regexp.MustCompile("^`?([^\\s`]+?):(\\d+)`?\\s+[-–—]\\s+(.+)$")
+
+// ImprovementLocation splits an improvement suggestion into the file and line it refers to
+// and the remaining text. ok is false for suggestions that aren't tied to a line.
+func ImprovementLocation(improvement string) (file string, line int, text string, ok bool) {
+	matches := improvementLocationRegex.FindStringSubmatch(strings.TrimSpace(improvement))
+	if matches == nil {
+		return "", 0, improvement, false
+	}
+	line, err := strconv.Atoi(matches[2])
+	if err != nil || line <= 0 {
+		return "", 0, improvement, false
+	}
+	return matches[1], line, matches[3], true
+}
+
+// buildReviewPrompt creates a prompt for line-anchored review comments
+func (s *Service) buildReviewPrompt(diffContent string) string {
+	return fmt.Sprintf(`Review the following git diff as an experienced code reviewer would on a pull request.
+
+Each diff line is prefixed with its line number. For added and unchanged lines this is the line number in the new file; for deleted lines (marked with -) it is the line number in the old file.
+
+Return ONLY a JSON array. Each element must be an object with these exact fields:
+- file: the file path exactly as shown after "File:"
+- line: the line number the comment refers to (integer)
+- severity: one of "info", "warning", "error"
+- comment: plain text review comment (1-3 sentences)
+
+Only comment on lines that appear in the diff. Return [] if there is nothing worth commenting on.
+
+Git diff:
+%s
+
+RESPOND ONLY WITH VALID JSON - no markdown, no code blocks, no extra text.`, diffContent)
 }
 
 // buildExplanationPrompt creates a prompt for change explanations
@@ -342,64 +700,91 @@ Git diff:
 Provide a clear, comprehensive explanation.`, diffContent)
 }
 
+// buildSummaryPrompt creates the prompt for a one-paragraph summary
+func (s *Service) buildSummaryPrompt(diffContent string) string {
+	return fmt.Sprintf(`Summarize the following git diff in one short paragraph (2-4 sentences) of plain text.
+Say what changed and why it matters. No headings, lists, Markdown or preamble.
+
+Git diff:
+%s`, diffContent)
+}
+
+// messages wraps a prompt in the chat messages sent to the API, adding a system message
+// with the response language when one is configured
+func (s *Service) messages(prompt string) []openai.ChatCompletionMessage {
+	var messages []openai.ChatCompletionMessage
+	if s.config.Language != "" {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role: openai.ChatMessageRoleSystem,
+			Content: fmt.Sprintf(`Respond in %s. Keep code, identifiers, file paths, JSON keys and `+
+				`conventional commit types such as feat, fix and chore in English; write all other prose in %s.`,
+				s.config.Language, s.config.Language),
+		})
+	}
+	return append(messages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: prompt,
+	})
+}
+
 // callAI makes a request to the AI service
 func (s *Service) callAI(ctx context.Context, prompt string) (string, error) {
 	req := openai.ChatCompletionRequest{
-		Model: s.config.Model,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			},
-		},
+		Model:               s.config.Model,
+		Messages:            s.messages(prompt),
 		MaxCompletionTokens: s.config.MaxCompletionTokens,
 	}
 
-	resp, err := s.client.CreateChatCompletion(ctx, req)
+	started := time.Now()
+	content, err := s.provider.Complete(ctx, req)
 	if err != nil {
+		s.logExchange(prompt, "", started, err)
 		return "", err
 	}
 
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no response from AI service")
-	}
-
-	return resp.Choices[0].Message.Content, nil
+	s.logExchange(prompt, content, started, nil)
+	s.setLastRawResponse(content)
+	return content, nil
 }
 
-// callAIStream makes a streaming request to the AI service and writes to stdout
-// It will only display output if stdout is a TTY (interactive terminal)
-func (s *Service) callAIStream(ctx context.Context, prompt string, writer io.Writer) (string, error) {
+// callAIStream makes a streaming request to the AI service and writes to StreamOutput
+// It will only display output if StreamOutput is a TTY (interactive terminal)
+func (s *Service) callAIStream(ctx context.Context, prompt string) (string, error) {
+	writer := s.config.StreamOutput
 	// Only enable output if we're writing to a terminal
-	shouldOutput := isatty.IsTerminal(os.Stdout.Fd())
-	return s.callAIStreamInternal(ctx, prompt, writer, shouldOutput)
+	shouldOutput := false
+	if file, ok := writer.(*os.File); ok {
+		shouldOutput = isatty.IsTerminal(file.Fd())
+	}
+	return s.callAIStreamInternal(ctx, prompt, writer, shouldOutput, false)
 }
 
 // callAIStreamQuiet makes a streaming request without writing to stdout
 func (s *Service) callAIStreamQuiet(ctx context.Context, prompt string) (string, error) {
-	return s.callAIStreamInternal(ctx, prompt, nil, false)
+	return s.callAIStreamInternal(ctx, prompt, nil, false, false)
+}
+
+// callAIStreamJSON makes a quiet streaming request for a JSON object response. With
+// JSONResponseFormat enabled the provider is asked to guarantee valid JSON.
+func (s *Service) callAIStreamJSON(ctx context.Context, prompt string) (string, error) {
+	return s.callAIStreamInternal(ctx, prompt, nil, false, s.config.JSONResponseFormat)
 }
 
 // callAIStreamInternal makes a streaming request to the AI service
-func (s *Service) callAIStreamInternal(ctx context.Context, prompt string, writer io.Writer, writeOutput bool) (string, error) {
+func (s *Service) callAIStreamInternal(ctx context.Context, prompt string, writer io.Writer, writeOutput, jsonObject bool) (string, error) {
 	req := openai.ChatCompletionRequest{
-		Model: s.config.Model,
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: prompt,
-			},
-		},
+		Model:               s.config.Model,
+		Messages:            s.messages(prompt),
 		MaxCompletionTokens: s.config.MaxCompletionTokens,
 		Stream:              true,
 	}
-
-	stream, err := s.client.CreateChatCompletionStream(ctx, req)
-	if err != nil {
-		return "", err
+	if jsonObject {
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
 	}
-	defer stream.Close()
 
+	started := time.Now()
 	var fullResponse strings.Builder
 	var bufferedWriter *bufio.Writer
 
@@ -408,30 +793,90 @@ func (s *Service) callAIStreamInternal(ctx context.Context, prompt string, write
 		bufferedWriter = bufio.NewWriter(writer)
 	}
 
-	for {
-		response, err := stream.Recv()
-		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return "", err
+	s.mu.Lock()
+	s.lastStreamFallback = false
+	s.mu.Unlock()
+
+	err := s.provider.Stream(ctx, req, func(content string) {
+		fullResponse.WriteString(content)
+		// Write to the provided writer if writeOutput is true
+		if writeOutput && bufferedWriter != nil {
+			bufferedWriter.WriteString(content)
+			// Flush after each token to ensure immediate display
+			bufferedWriter.Flush()
+		}
+	})
+	if err != nil && streamingUnsupported(ctx, err) {
+		// Some OpenAI-compatible endpoints reject streaming or break off mid-stream;
+		// repeat the request without it
+		s.logExchange(prompt, fullResponse.String(), started, err)
+		s.mu.Lock()
+		s.lastStreamFallback = true
+		s.mu.Unlock()
+		if writeOutput && bufferedWriter != nil {
+			bufferedWriter.WriteString("\n(streaming unavailable, falling back)\n")
+			bufferedWriter.Flush()
 		}
 
-		if len(response.Choices) > 0 && response.Choices[0].Delta.Content != "" {
-			content := response.Choices[0].Delta.Content
-			fullResponse.WriteString(content)
-			// Write to the provided writer if writeOutput is true
-			if writeOutput && bufferedWriter != nil {
-				bufferedWriter.WriteString(content)
-				// Flush after each token to ensure immediate display
-				bufferedWriter.Flush()
-			}
+		req.Stream = false
+		started = time.Now()
+		var content string
+		content, err = s.provider.Complete(ctx, req)
+		fullResponse.Reset()
+		fullResponse.WriteString(content)
+		if err == nil && writeOutput && bufferedWriter != nil {
+			bufferedWriter.WriteString(content)
+			bufferedWriter.Flush()
 		}
 	}
+	if err != nil {
+		s.logExchange(prompt, fullResponse.String(), started, err)
+		return "", err
+	}
 
+	s.logExchange(prompt, fullResponse.String(), started, nil)
+	s.setLastRawResponse(fullResponse.String())
 	return fullResponse.String(), nil
 }
 
+// streamingUnsupported reports whether a failed streaming request is worth repeating
+// without streaming: not when it was canceled or failed for a reason, such as a missing
+// key, rate limiting or an oversized prompt, that a plain request would hit too
+func streamingUnsupported(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	for _, target := range []error{ErrNoAPIKey, ErrRateLimited, ErrContextLengthExceeded} {
+		if errors.Is(err, target) {
+			return false
+		}
+	}
+	return true
+}
+
+// LastStreamFallback reports whether the most recent request fell back from streaming to
+// a regular request because the endpoint failed to stream
+func (s *Service) LastStreamFallback() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastStreamFallback
+}
+
+// setLastRawResponse records a completion for LastRawResponse
+func (s *Service) setLastRawResponse(response string) {
+	s.mu.Lock()
+	s.lastRawResponse = response
+	s.mu.Unlock()
+}
+
+// LastRawResponse returns the most recent completion exactly as the model returned it,
+// before it was parsed, cleaned up or rendered
+func (s *Service) LastRawResponse() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastRawResponse
+}
+
 // parseAnalysisResponse parses the AI response into AnalysisResult
 func (s *Service) parseAnalysisResponse(response string) (*AnalysisResult, error) {
 	// Clean the response - remove any extra text before/after JSON
@@ -443,17 +888,7 @@ func (s *Service) parseAnalysisResponse(response string) (*AnalysisResult, error
 
 	if startIdx == -1 || endIdx == -1 || startIdx >= endIdx {
 		// No JSON found, return basic result
-		return &AnalysisResult{
-			Summary:          response,
-			Improvements:     []string{},
-			Issues:           []string{},
-			Explanations:     []string{response},
-			CommitMessage:    "Update code",
-			PRDescription:    response,
-			CodeQuality:      "Unknown",
-			SecurityNotes:    []string{},
-			PerformanceNotes: []string{},
-		}, nil
+		return s.fallbackAnalysisResult(response), nil
 	}
 
 	// Extract just the JSON part
@@ -463,17 +898,7 @@ func (s *Service) parseAnalysisResponse(response string) (*AnalysisResult, error
 	var jsonMap map[string]interface{}
 	if err := json.Unmarshal([]byte(jsonStr), &jsonMap); err != nil {
 		// If JSON parsing fails, return basic result
-		return &AnalysisResult{
-			Summary:          response,
-			Improvements:     []string{},
-			Issues:           []string{},
-			Explanations:     []string{response},
-			CommitMessage:    "Update code",
-			PRDescription:    response,
-			CodeQuality:      "Unknown",
-			SecurityNotes:    []string{},
-			PerformanceNotes: []string{},
-		}, nil
+		return s.fallbackAnalysisResult(response), nil
 	}
 
 	// Extract fields from the map with proper handling
@@ -489,6 +914,12 @@ func (s *Service) parseAnalysisResponse(response string) (*AnalysisResult, error
 		PerformanceNotes: extractStringArrayFromMap(jsonMap, "performance_notes"),
 	}
 
+	if !s.config.AnalysisIncludesCommitAndPR {
+		// Ignore fields the model added on its own
+		result.CommitMessage = ""
+		result.PRDescription = ""
+	}
+
 	// Sanitize all string fields to handle cases where AI returns JSON in string fields
 	result.Summary = sanitizeField(result.Summary, "Summary")
 	result.CodeQuality = sanitizeField(result.CodeQuality, "CodeQuality")
@@ -505,6 +936,94 @@ func (s *Service) parseAnalysisResponse(response string) (*AnalysisResult, error
 	return result, nil
 }
 
+// fallbackAnalysisResult wraps a response that isn't valid JSON so it can still be shown
+func (s *Service) fallbackAnalysisResult(response string) *AnalysisResult {
+	result := &AnalysisResult{
+		Summary:          response,
+		Improvements:     []string{},
+		Issues:           []string{},
+		Explanations:     []string{response},
+		CodeQuality:      "Unknown",
+		SecurityNotes:    []string{},
+		PerformanceNotes: []string{},
+	}
+	if s.config.AnalysisIncludesCommitAndPR {
+		result.CommitMessage = "Update code"
+		result.PRDescription = response
+	}
+	return result
+}
+
+// parseReviewResponse extracts the JSON array of review comments from the AI response
+func parseReviewResponse(response string) ([]ReviewComment, error) {
+	cleanedResponse := strings.TrimSpace(response)
+
+	startIdx := strings.Index(cleanedResponse, "[")
+	endIdx := strings.LastIndex(cleanedResponse, "]")
+	if startIdx == -1 || endIdx == -1 || startIdx >= endIdx {
+		return nil, fmt.Errorf("%w: no JSON array found in response", ErrInvalidResponse)
+	}
+
+	var comments []ReviewComment
+	if err := json.Unmarshal([]byte(cleanedResponse[startIdx:endIdx+1]), &comments); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	return comments, nil
+}
+
+// anchorReviewComments resolves each comment to a hunk and line in files, dropping comments that don't match the diff
+func anchorReviewComments(files []parser.FileDiff, comments []ReviewComment) []ReviewComment {
+	result := make([]ReviewComment, 0, len(comments))
+
+	for _, comment := range comments {
+		comment.Comment = strings.TrimSpace(comment.Comment)
+		if comment.Comment == "" || comment.Line <= 0 {
+			continue
+		}
+
+		path := strings.TrimPrefix(strings.TrimPrefix(comment.File, "./"), "b/")
+		for _, file := range files {
+			if file.NewPath != path && file.OldPath != path {
+				continue
+			}
+			hunkIdx, lineIdx, ok := findDiffLine(file, comment.Line)
+			if !ok {
+				break
+			}
+			comment.File = file.NewPath
+			comment.HunkIndex = hunkIdx
+			comment.LineIndex = lineIdx
+			comment.Severity = strings.ToLower(strings.TrimSpace(comment.Severity))
+			result = append(result, comment)
+			break
+		}
+	}
+
+	return result
+}
+
+// findDiffLine locates a line number in a file diff, preferring new-side line numbers over deleted lines
+func findDiffLine(file parser.FileDiff, lineNum int) (int, int, bool) {
+	for hunkIdx, hunk := range file.Hunks {
+		for lineIdx, line := range hunk.Lines {
+			if line.Type != parser.LineDeleted && line.NewLineNum == lineNum {
+				return hunkIdx, lineIdx, true
+			}
+		}
+	}
+
+	for hunkIdx, hunk := range file.Hunks {
+		for lineIdx, line := range hunk.Lines {
+			if line.Type == parser.LineDeleted && line.OldLineNum == lineNum {
+				return hunkIdx, lineIdx, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
 // cleanStringArray removes empty strings and sanitizes array items
 func cleanStringArray(arr []string) []string {
 	result := make([]string, 0, len(arr))
@@ -0,0 +1,42 @@
+package ai
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// LoadCACerts returns the system root CAs plus the PEM certificates in path, for
+// providers reached through a proxy or gateway with an internal CA
+func LoadCACerts(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CA certificate: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("read CA certificate: no PEM certificates found in " + path)
+	}
+	return pool, nil
+}
+
+// newHTTPClient builds the client used for API requests. It honors HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY, and trusts config.RootCAs when set.
+func newHTTPClient(config *Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if config.RootCAs != nil {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    config.RootCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return &http.Client{Transport: transport}
+}
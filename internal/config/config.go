@@ -6,8 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/x/ansi"
 )
 
 const (
@@ -22,19 +26,111 @@ const (
 	DiffStyleFilled  = "filled"
 )
 
+// DefaultCommitStyleExamples is how many recent commit subjects are shown to the AI as style examples
+const DefaultCommitStyleExamples = 10
+
+// DefaultMaxFiles is how many changed files are shown before the diff is cut down to the
+// largest ones, keeping huge changesets responsive
+const DefaultMaxFiles = 2000
+
+// DefaultAIMaxFiles is how many changed files, the largest first, are sent to the AI
+const DefaultAIMaxFiles = 100
+
+// DefaultSplitMinWidth is the narrowest terminal that still fits two 40-column split
+// panes; below it the split view falls back to unified rendering
+const DefaultSplitMinWidth = 83
+
+// DefaultUntrackedMaxSize is the size in bytes above which untracked files are listed
+// without their content, so a stray log or dump doesn't flood the diff
+const DefaultUntrackedMaxSize = 1 << 20
+
+// DefaultMovedMinLines is the shortest block, in non-blank lines, highlighted as moved
+// code; shorter matches are too often coincidental
+const DefaultMovedMinLines = 3
+
+// DefaultMaxLineLength is how many characters of a line are rendered before the rest is
+// cut, so minified or generated files don't stall highlighting
+const DefaultMaxLineLength = 1000
+
+// DefaultHighlightMaxLines is the number of diff lines above which a file is rendered
+// without syntax highlighting, which dominates render time on large files
+const DefaultHighlightMaxLines = 5000
+
+// DefaultCollapsePaths are the generated/vendored paths collapsed when collapse_paths is not set
+var DefaultCollapsePaths = []string{
+	"go.sum",
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Cargo.lock",
+	"Gemfile.lock",
+	"poetry.lock",
+	"composer.lock",
+	"*.min.js",
+	"*.min.css",
+	"vendor/",
+	"node_modules/",
+	"dist/",
+}
+
 type Config struct {
 	Interactive      *bool  `json:"interactive,omitempty"`
 	Unified          *bool  `json:"unified,omitempty"`
 	NoColor          *bool  `json:"no_color,omitempty"`
+	ForceColor       *bool  `json:"force_color,omitempty"`
+	Wrap             *bool  `json:"wrap,omitempty"`
+	Compact          *bool  `json:"compact,omitempty"`
 	DiffMode         string `json:"diff_mode,omitempty"`
 	DiffStyle        string `json:"diff_style,omitempty"`
 	AddedTextColor   string `json:"added_text_color,omitempty"`
 	DeletedTextColor string `json:"deleted_text_color,omitempty"`
+	// SplitMinWidth is the terminal width below which the split view renders unified (0 never switches)
+	SplitMinWidth *int `json:"split_min_width,omitempty"`
+	// UntrackedMaxSize is the size in bytes above which untracked files are listed without content (0 never omits)
+	UntrackedMaxSize *int64 `json:"untracked_max_size,omitempty"`
+	// MovedMinLines is the shortest block, in non-blank lines, highlighted as moved (0 disables)
+	MovedMinLines *int `json:"moved_min_lines,omitempty"`
+	// MaxLineLength is how many characters of a line are rendered before the rest is cut (0 shows all)
+	MaxLineLength *int `json:"max_line_length,omitempty"`
+	// HighlightMaxLines is how many diff lines a file may have and still be syntax highlighted (0 always highlights)
+	HighlightMaxLines *int `json:"highlight_max_lines,omitempty"`
+	// MaxFiles is how many changed files are shown before the largest are kept (0 shows all)
+	MaxFiles *int `json:"max_files,omitempty"`
+	// SyntaxThemeOverrides maps a language name or file extension to the chroma style it is highlighted with
+	SyntaxThemeOverrides map[string]string `json:"syntax_theme_overrides,omitempty"`
+	// ColumnSeparator replaces the "│" between the split-view columns; it must be one column wide
+	ColumnSeparator string `json:"column_separator,omitempty"`
+	// SkipMarker replaces the "⋯" shown where unchanged lines between hunks are folded away
+	SkipMarker string `json:"skip_marker,omitempty"`
+	// FileLabels prefixes interactive file list entries with a language label (default true)
+	FileLabels *bool `json:"file_labels,omitempty"`
+	// CollapsePaths lists globs for files that start collapsed in interactive mode
+	CollapsePaths []string `json:"collapse_paths,omitempty"`
+	// CommitNeverAddAll disables staging everything ("git add .") when committing with no staged files
+	CommitNeverAddAll *bool `json:"commit_never_add_all,omitempty"`
+	// CommitStyleContext includes recent commit subjects in the commit prompt (default true)
+	CommitStyleContext *bool `json:"commit_style_context,omitempty"`
 	// AI Configuration
 	AIEnabled     *bool  `json:"ai_enabled,omitempty"`
 	OpenAIAPIKey  string `json:"openai_api_key,omitempty"`
 	OpenAIModel   string `json:"openai_model,omitempty"`
 	OpenAIBaseURL string `json:"openai_base_url,omitempty"`
+	// AICACert is a PEM file of extra root CAs trusted for AI requests, e.g. a proxy's internal CA
+	AICACert string `json:"ai_ca_cert,omitempty"`
+	// AIRedactSecrets replaces likely secrets in diffs before they are sent to the AI (default true)
+	AIRedactSecrets *bool `json:"ai_redact_secrets,omitempty"`
+	// AIRedactPatterns are extra regular expressions for values to redact
+	AIRedactPatterns []string `json:"ai_redact_patterns,omitempty"`
+	// AIMaxFiles is how many changed files, the largest first, are sent to the AI (0 sends all)
+	AIMaxFiles *int `json:"ai_max_files,omitempty"`
+	// AILanguage is the language AI responses are written in, e.g. "Portuguese"
+	AILanguage string `json:"ai_language,omitempty"`
+	// AIDebug logs every AI prompt and raw response to DebugLogPath
+	AIDebug *bool `json:"ai_debug,omitempty"`
+	// AIJSONResponseFormat asks the provider for a guaranteed JSON object in AI analysis
+	AIJSONResponseFormat *bool `json:"ai_json_response_format,omitempty"`
+	// AIAnalysisCommitPR also generates a commit message and PR description during AI analysis
+	AIAnalysisCommitPR *bool `json:"ai_analysis_commit_pr,omitempty"`
 }
 
 func Load() (*Config, error) {
@@ -51,18 +147,54 @@ func Load() (*Config, error) {
 		return &Config{}, fmt.Errorf("read config: %w", err)
 	}
 
+	cfg, err := Parse(data)
+	if err != nil {
+		return &Config{}, err
+	}
+	return cfg, nil
+}
+
+// Parse decodes and validates the contents of a config file
+func Parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
-		return &Config{}, fmt.Errorf("parse config: %w", err)
+		return nil, fmt.Errorf("parse config: %w", err)
 	}
 
 	if err := cfg.normalize(); err != nil {
-		return &Config{}, err
+		return nil, err
 	}
 
 	return &cfg, nil
 }
 
+// Template is written to a new config file; it sets a few common options to their defaults
+const Template = `{
+  "interactive": false,
+  "unified": false,
+  "diff_mode": "all",
+  "diff_style": "default"
+}
+`
+
+// CreateIfMissing writes Template to path, creating its directory, unless the file
+// already exists. It reports whether the file was created.
+func CreateIfMissing(path string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("stat config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false, fmt.Errorf("create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(Template), 0o644); err != nil {
+		return false, fmt.Errorf("write config: %w", err)
+	}
+	return true, nil
+}
+
 func DefaultPath() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -71,6 +203,15 @@ func DefaultPath() (string, error) {
 	return filepath.Join(configDir, "critica", "config.json"), nil
 }
 
+// DebugLogPath returns the file AI prompts and responses are logged to when ai_debug is on
+func DebugLogPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "critica", "ai-debug.log"), nil
+}
+
 func (c *Config) normalize() error {
 	mode := strings.ToLower(strings.TrimSpace(c.DiffMode))
 	if mode == "" {
@@ -104,9 +245,216 @@ func (c *Config) normalize() error {
 	}
 	c.DeletedTextColor = deleted
 
+	if c.SplitMinWidth != nil && *c.SplitMinWidth < 0 {
+		return fmt.Errorf("invalid split_min_width %d: must not be negative", *c.SplitMinWidth)
+	}
+
+	if c.UntrackedMaxSize != nil && *c.UntrackedMaxSize < 0 {
+		return fmt.Errorf("invalid untracked_max_size %d: must not be negative", *c.UntrackedMaxSize)
+	}
+
+	if c.MovedMinLines != nil && *c.MovedMinLines < 0 {
+		return fmt.Errorf("invalid moved_min_lines %d: must not be negative", *c.MovedMinLines)
+	}
+
+	if c.MaxLineLength != nil && *c.MaxLineLength < 0 {
+		return fmt.Errorf("invalid max_line_length %d: must not be negative", *c.MaxLineLength)
+	}
+
+	if c.HighlightMaxLines != nil && *c.HighlightMaxLines < 0 {
+		return fmt.Errorf("invalid highlight_max_lines %d: must not be negative", *c.HighlightMaxLines)
+	}
+
+	if c.MaxFiles != nil && *c.MaxFiles < 0 {
+		return fmt.Errorf("invalid max_files %d: must not be negative", *c.MaxFiles)
+	}
+
+	if c.AIMaxFiles != nil && *c.AIMaxFiles < 0 {
+		return fmt.Errorf("invalid ai_max_files %d: must not be negative", *c.AIMaxFiles)
+	}
+
+	if c.ColumnSeparator != "" && ansi.StringWidth(c.ColumnSeparator) != 1 {
+		return fmt.Errorf("invalid column_separator %q: must be a single column wide", c.ColumnSeparator)
+	}
+
+	if strings.ContainsAny(c.SkipMarker, "\r\n") || (c.SkipMarker != "" && ansi.StringWidth(c.SkipMarker) == 0) {
+		return fmt.Errorf("invalid skip_marker %q: must be visible text on one line", c.SkipMarker)
+	}
+
+	if c.SyntaxThemeOverrides != nil {
+		overrides := make(map[string]string, len(c.SyntaxThemeOverrides))
+		for language, style := range c.SyntaxThemeOverrides {
+			style = strings.TrimSpace(style)
+			if _, ok := styles.Registry[style]; !ok {
+				return fmt.Errorf("invalid syntax_theme_overrides entry %q: unknown style %q", language, style)
+			}
+			// Extensions may be written as "go", ".go" or "*.go"
+			key := strings.ToLower(strings.TrimLeft(strings.TrimSpace(language), "*."))
+			if key == "" {
+				return fmt.Errorf("invalid syntax_theme_overrides entry %q: missing language", language)
+			}
+			overrides[key] = style
+		}
+		c.SyntaxThemeOverrides = overrides
+	}
+
+	c.AICACert = strings.TrimSpace(c.AICACert)
+	c.AILanguage = strings.TrimSpace(c.AILanguage)
+
+	for _, pattern := range c.AIRedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid ai_redact_patterns entry %q: %w", pattern, err)
+		}
+	}
+
+	if c.CollapsePaths != nil {
+		patterns := make([]string, 0, len(c.CollapsePaths))
+		for _, pattern := range c.CollapsePaths {
+			pattern = filepath.ToSlash(strings.TrimSpace(pattern))
+			if pattern == "" {
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+		c.CollapsePaths = patterns
+	}
+
 	return nil
 }
 
+// CollapsePatterns returns the configured collapse globs, falling back to DefaultCollapsePaths
+func (c *Config) CollapsePatterns() []string {
+	if c == nil || c.CollapsePaths == nil {
+		return DefaultCollapsePaths
+	}
+	return c.CollapsePaths
+}
+
+// SplitThreshold returns the width below which split diffs are rendered unified, or 0
+// to always honor the split view
+func (c *Config) SplitThreshold() int {
+	if c == nil || c.SplitMinWidth == nil {
+		return DefaultSplitMinWidth
+	}
+	return *c.SplitMinWidth
+}
+
+// UntrackedSizeLimit returns the size in bytes above which untracked files are listed
+// without their content, or 0 for no limit
+func (c *Config) UntrackedSizeLimit() int64 {
+	if c == nil || c.UntrackedMaxSize == nil {
+		return DefaultUntrackedMaxSize
+	}
+	return *c.UntrackedMaxSize
+}
+
+// MovedThreshold returns the shortest block highlighted as moved code, or 0 when moved
+// code detection is off
+func (c *Config) MovedThreshold() int {
+	if c == nil || c.MovedMinLines == nil {
+		return DefaultMovedMinLines
+	}
+	return *c.MovedMinLines
+}
+
+// LineLengthLimit returns how many characters of a line are rendered before the rest is
+// cut, or 0 for no limit
+func (c *Config) LineLengthLimit() int {
+	if c == nil || c.MaxLineLength == nil {
+		return DefaultMaxLineLength
+	}
+	return *c.MaxLineLength
+}
+
+// HighlightLineLimit returns the number of diff lines above which a file is rendered
+// without syntax highlighting, or 0 for no limit
+func (c *Config) HighlightLineLimit() int {
+	if c == nil || c.HighlightMaxLines == nil {
+		return DefaultHighlightMaxLines
+	}
+	return *c.HighlightMaxLines
+}
+
+// FileLimit returns how many changed files are shown before the diff is cut down to the
+// largest ones, or 0 for no limit
+func (c *Config) FileLimit() int {
+	if c == nil || c.MaxFiles == nil {
+		return DefaultMaxFiles
+	}
+	return *c.MaxFiles
+}
+
+// AIFileLimit returns how many changed files are sent to the AI, or 0 for no limit
+func (c *Config) AIFileLimit() int {
+	if c == nil || c.AIMaxFiles == nil {
+		return DefaultAIMaxFiles
+	}
+	return *c.AIMaxFiles
+}
+
+// ShowFileLabels reports whether the interactive file list labels files with their language
+func (c *Config) ShowFileLabels() bool {
+	return c == nil || c.FileLabels == nil || *c.FileLabels
+}
+
+// NeverAddAll reports whether commits are restricted to files the user staged explicitly
+func (c *Config) NeverAddAll() bool {
+	return c != nil && c.CommitNeverAddAll != nil && *c.CommitNeverAddAll
+}
+
+// CommitStyleExamples returns how many recent commit subjects to include as style
+// examples when generating commit messages, or 0 when commit_style_context is off
+func (c *Config) CommitStyleExamples() int {
+	if c != nil && c.CommitStyleContext != nil && !*c.CommitStyleContext {
+		return 0
+	}
+	return DefaultCommitStyleExamples
+}
+
+// RedactSecrets reports whether likely secrets are redacted from AI prompts
+func (c *Config) RedactSecrets() bool {
+	return c == nil || c.AIRedactSecrets == nil || *c.AIRedactSecrets
+}
+
+// RedactPatterns returns the extra redaction regular expressions from ai_redact_patterns
+func (c *Config) RedactPatterns() []string {
+	if c == nil {
+		return nil
+	}
+	return c.AIRedactPatterns
+}
+
+// Language returns the language AI responses are written in, or "" for the model's default
+func (c *Config) Language() string {
+	if c == nil {
+		return ""
+	}
+	return c.AILanguage
+}
+
+// DebugAI reports whether AI prompts and responses are logged
+func (c *Config) DebugAI() bool {
+	return c != nil && c.AIDebug != nil && *c.AIDebug
+}
+
+// CACertPath returns the ai_ca_cert file, or "" to use only the system root CAs
+func (c *Config) CACertPath() string {
+	if c == nil {
+		return ""
+	}
+	return c.AICACert
+}
+
+// JSONResponseFormat reports whether AI analysis requests response_format json_object
+func (c *Config) JSONResponseFormat() bool {
+	return c != nil && c.AIJSONResponseFormat != nil && *c.AIJSONResponseFormat
+}
+
+// AnalysisCommitPR reports whether AI analysis also suggests a commit message and PR description
+func (c *Config) AnalysisCommitPR() bool {
+	return c != nil && c.AIAnalysisCommitPR != nil && *c.AIAnalysisCommitPR
+}
+
 func normalizeHexColor(value string) (string, error) {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// State holds view preferences remembered between interactive sessions. It is kept
+// apart from config.json so the user's config file is never rewritten.
+type State struct {
+	// Unified is whether the diff view was unified when the last session ended
+	Unified *bool `json:"unified,omitempty"`
+	// PreviewCollapsed is whether the preview pane was collapsed when the last session ended
+	PreviewCollapsed *bool `json:"preview_collapsed,omitempty"`
+}
+
+// StatePath returns the file interactive view preferences are saved to
+func StatePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "critica", "state.json"), nil
+}
+
+// LoadState reads the saved view preferences; a missing file yields an empty state
+func LoadState() (*State, error) {
+	path, err := StatePath()
+	if err != nil {
+		return &State{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &State{}, nil
+		}
+		return &State{}, fmt.Errorf("read state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return &State{}, fmt.Errorf("parse state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveState writes the view preferences for the next interactive session
+func SaveState(state *State) error {
+	path, err := StatePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+	return nil
+}
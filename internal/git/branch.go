@@ -6,7 +6,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // GetAllBranches returns a list of all branches (local and remote)
@@ -149,7 +152,8 @@ func GetBranchDiff(path, fromBranch, toBranch string) (string, error) {
 
 	// Use a safer approach - compare the branches directly
 	// For PR descriptions, we want to show changes in fromBranch that are not in toBranch
-	cmd := exec.Command("git", "diff", "-U5", "--no-color", toBranch, fromBranch)
+	args := append([]string{"diff", "-U5", "--no-color"}, reverseArgs()...)
+	cmd := exec.Command("git", append(args, toBranch, fromBranch)...)
 	cmd.Dir = workDir
 
 	var stdout, stderr bytes.Buffer
@@ -173,6 +177,117 @@ func GetBranchDiff(path, fromBranch, toBranch string) (string, error) {
 	return stdout.String(), nil
 }
 
+// BranchCommitSummary describes the commits on a source branch that aren't on its target
+type BranchCommitSummary struct {
+	Count int
+	// Authors lists each author once, most commits first
+	Authors []string
+	// First and Last are the author dates of the oldest and newest commit
+	First time.Time
+	Last  time.Time
+}
+
+// GetBranchCommitSummary summarizes the commits in target..source (git log)
+func GetBranchCommitSummary(path, source, target string) (*BranchCommitSummary, error) {
+	if source == "" {
+		return nil, fmt.Errorf("source branch name is empty")
+	}
+	if target == "" {
+		return nil, fmt.Errorf("target branch name is empty")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	cmd := exec.Command("git", "log", "--format=%at%x09%an", target+".."+source, "--")
+	cmd.Dir = workDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch commits: %w", err)
+	}
+
+	summary := &BranchCommitSummary{}
+	commitsByAuthor := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		stamp, author, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		seconds, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		date := time.Unix(seconds, 0)
+		if summary.Count == 0 || date.Before(summary.First) {
+			summary.First = date
+		}
+		if summary.Count == 0 || date.After(summary.Last) {
+			summary.Last = date
+		}
+		summary.Count++
+
+		if commitsByAuthor[author] == 0 {
+			summary.Authors = append(summary.Authors, author)
+		}
+		commitsByAuthor[author]++
+	}
+
+	sort.SliceStable(summary.Authors, func(i, j int) bool {
+		return commitsByAuthor[summary.Authors[i]] > commitsByAuthor[summary.Authors[j]]
+	})
+	return summary, nil
+}
+
+// Describe renders the summary as one line, with dates relative to now, e.g.
+// "3 commits by Ana, Bo; oldest 5 days ago (2024-05-01), newest 2 hours ago (2024-05-06)"
+func (s *BranchCommitSummary) Describe(now time.Time) string {
+	if s == nil || s.Count == 0 {
+		return ""
+	}
+
+	if s.Count == 1 {
+		return fmt.Sprintf("1 commit by %s, %s (%s)", s.Authors[0], relativeTime(s.Last, now), s.Last.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%d commits by %s; oldest %s (%s), newest %s (%s)", s.Count, strings.Join(s.Authors, ", "),
+		relativeTime(s.First, now), s.First.Format("2006-01-02"),
+		relativeTime(s.Last, now), s.Last.Format("2006-01-02"))
+}
+
+// relativeTime describes t relative to now in the largest whole unit, e.g. "3 days ago"
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"week", 7 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		if n := int(d / unit.size); n >= 1 {
+			if n == 1 {
+				return "1 " + unit.name + " ago"
+			}
+			return fmt.Sprintf("%d %ss ago", n, unit.name)
+		}
+	}
+	return "just now"
+}
+
 // GetCurrentBranch returns the current branch name
 func GetCurrentBranch(path string) (string, error) {
 	absPath, err := filepath.Abs(path)
@@ -186,7 +301,13 @@ func GetCurrentBranch(path string) (string, error) {
 		workDir = filepath.Dir(absPath)
 	}
 
-	cmd := exec.Command("git", "branch", "--show-current")
+	// git branch --show-current needs git 2.22; older versions name the branch through
+	// rev-parse, which prints HEAD instead of nothing when it is detached
+	args := []string{"branch", "--show-current"}
+	if olderThan(MinVersion) {
+		args = []string{"rev-parse", "--abbrev-ref", "HEAD"}
+	}
+	cmd := exec.Command("git", args...)
 	cmd.Dir = workDir
 
 	output, err := cmd.Output()
@@ -194,5 +315,9 @@ func GetCurrentBranch(path string) (string, error) {
 		return "", fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
 }
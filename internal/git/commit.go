@@ -1,15 +1,23 @@
 package git
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 // StageAllFiles stages all files in the repository
 func StageAllFiles(path string) error {
+	if skipForDryRun("add", ".") {
+		return nil
+	}
+
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("failed to get absolute path: %w", err)
@@ -33,6 +41,10 @@ func StageAllFiles(path string) error {
 
 // CreateCommit creates a commit with the given message
 func CreateCommit(path, message string) error {
+	if skipForDryRun("commit", "-m", message) {
+		return nil
+	}
+
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return fmt.Errorf("failed to get absolute path: %w", err)
@@ -54,6 +66,86 @@ func CreateCommit(path, message string) error {
 	return nil
 }
 
+// GetHeadHash returns the full hash of the commit HEAD points to
+func GetHeadHash(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = workDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// VerifyCommit checks that ref names an existing commit, such as HEAD~3
+func VerifyCommit(path, ref string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	cmd.Dir = workDir
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("unknown commit %s", ref)
+	}
+	return nil
+}
+
+// GetCommitDiff retrieves the changes made by a single commit (git show), diffing merge
+// commits against their first parent
+func GetCommitDiff(path, commit string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	args := append([]string{"show", "--format=", "--first-parent", "--no-color"}, contextArgs()...)
+	args = append(args, prefixArgs()...)
+	cmd := exec.Command("git", append(args, commit, "--")...)
+	cmd.Dir = workDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg != "" {
+			return "", fmt.Errorf("git show failed: %s", errMsg)
+		}
+		return "", fmt.Errorf("git show failed: %w", err)
+	}
+
+	return stdout.String(), nil
+}
+
 // HasStagedChanges checks if there are any staged changes
 func HasStagedChanges(path string) (bool, error) {
 	absPath, err := filepath.Abs(path)
@@ -112,11 +204,81 @@ func GetStagedFiles(path string) ([]string, error) {
 	return files, nil
 }
 
-// PushBranch pushes the current branch to the remote repository
+// GetRecentCommitSubjects returns the subject lines of the last n commits on the current branch,
+// newest first. A repository without commits yields an empty list.
+func GetRecentCommitSubjects(path string, n int) ([]string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	// Without a HEAD there is no history to read
+	check := exec.Command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	check.Dir = workDir
+	if err := check.Run(); err != nil {
+		return []string{}, nil
+	}
+
+	cmd := exec.Command("git", "log", "--no-merges", "--format=%s", "-n", strconv.Itoa(n))
+	cmd.Dir = workDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent commits: %w", err)
+	}
+
+	subjects := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(subjects) == 1 && subjects[0] == "" {
+		return []string{}, nil
+	}
+
+	return subjects, nil
+}
+
+// ErrAuthRequired is returned when a push fails because the remote needs credentials
+var ErrAuthRequired = errors.New("authentication required: configure a credential helper or SSH key for the push remote")
+
+// authErrorMarkers are stderr fragments git and common remotes print on credential failures
+var authErrorMarkers = []string{
+	"authentication failed",
+	"could not read username",
+	"could not read password",
+	"terminal prompts disabled",
+	"permission denied (publickey",
+	"invalid username or password",
+	"the requested url returned error: 403",
+}
+
+// PushBranch pushes the current branch to its push target (see GetPushTarget).
+// The terminal is attached so credential helpers and SSH can prompt for secrets.
 func PushBranch(path string) error {
+	if skipForDryRun("push") {
+		return nil
+	}
+
+	cmd, stderr, err := PushCommand(path)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+
+	return PushResult(cmd.Run(), stderr.String())
+}
+
+// PushCommand prepares a "git push" for the current branch. Stderr is mirrored to the
+// terminal and captured in the returned buffer so failures can be explained with
+// PushResult; callers decide how stdin and stdout are attached.
+func PushCommand(path string) (*exec.Cmd, *bytes.Buffer, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
 	workDir := absPath
@@ -125,12 +287,56 @@ func PushBranch(path string) error {
 		workDir = filepath.Dir(absPath)
 	}
 
+	var stderr bytes.Buffer
 	cmd := exec.Command("git", "push")
 	cmd.Dir = workDir
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to push branch: %w", err)
+	return cmd, &stderr, nil
+}
+
+// PushResult converts the outcome of a push command into an error, reporting
+// ErrAuthRequired when stderr shows a credential failure
+func PushResult(err error, stderr string) error {
+	if err == nil {
+		return nil
 	}
 
-	return nil
+	lower := strings.ToLower(stderr)
+	for _, marker := range authErrorMarkers {
+		if strings.Contains(lower, marker) {
+			return fmt.Errorf("failed to push branch: %w", ErrAuthRequired)
+		}
+	}
+
+	if errMsg := strings.TrimSpace(stderr); errMsg != "" {
+		return fmt.Errorf("failed to push branch: %s", errMsg)
+	}
+	return fmt.Errorf("failed to push branch: %w", err)
+}
+
+// GetPushTarget returns the remote branch "git push" will update for the current
+// branch (for example "origin/main"), as resolved by git from branch.<name>.pushRemote,
+// remote.pushDefault, the upstream and push.default
+func GetPushTarget(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{push}")
+	cmd.Dir = workDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve push target: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
 }
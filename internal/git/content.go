@@ -0,0 +1,168 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// RevWorkingTree reads a file from the working tree
+	RevWorkingTree = ""
+	// RevIndex reads a file from the index (staging area)
+	RevIndex = ":"
+)
+
+// GetFileContent returns the content of a repository-relative file at the given revision.
+// Use RevWorkingTree for the working copy and RevIndex for the staged version.
+func GetFileContent(path, file, rev string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	if rev == RevWorkingTree {
+		root, err := repoRoot(workDir)
+		if err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		return string(content), nil
+	}
+
+	object := rev + ":" + file
+	if rev == RevIndex {
+		object = ":" + file
+	}
+
+	cmd := exec.Command("git", "show", object)
+	cmd.Dir = workDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg != "" {
+			return "", fmt.Errorf("failed to read %s: %s", object, errMsg)
+		}
+		return "", fmt.Errorf("failed to read %s: %w", object, err)
+	}
+
+	return stdout.String(), nil
+}
+
+// GetFullFileDiff returns a diff of a single file with the whole file as context.
+// The old and new sides are chosen by mode the same way GetDiffForMode compares them.
+// oldFile and newFile are the file's paths on each side, which differ for renames. ref,
+// when set, replaces HEAD as the old side of DiffModeAll, as in GetDiffForPathsAgainstRef.
+// Pass isNew or isDeleted for files that don't exist on one side.
+func GetFullFileDiff(path, oldFile, newFile string, mode DiffMode, ref string, isNew, isDeleted bool) (string, error) {
+	oldRev, newRev := "HEAD", RevWorkingTree
+	switch mode {
+	case DiffModeAll:
+		if ref != "" {
+			oldRev = ref
+		}
+	case DiffModeStaged:
+		newRev = RevIndex
+	case DiffModeUnstaged:
+		oldRev = RevIndex
+	}
+	if Reverse {
+		// isNew and isDeleted describe the reversed diff, so they still match the sides
+		oldRev, newRev = newRev, oldRev
+	}
+
+	var oldContent, newContent string
+	var err error
+	if !isNew {
+		if oldContent, err = GetFileContent(path, oldFile, oldRev); err != nil {
+			return "", err
+		}
+	}
+	if !isDeleted {
+		if newContent, err = GetFileContent(path, newFile, newRev); err != nil {
+			return "", err
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "critica-full-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	oldTemp, newTemp := "old", "new"
+	if err := os.WriteFile(filepath.Join(tmpDir, oldTemp), []byte(oldContent), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, newTemp), []byte(newContent), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	// Enough context lines to cover both sides, so the diff is a single hunk
+	context := strings.Count(oldContent, "\n") + strings.Count(newContent, "\n") + 1
+
+	args := append([]string{"diff", "--no-index", "--no-color", fmt.Sprintf("-U%d", context)}, prefixArgs()...)
+	cmd := exec.Command("git", append(args, "--", oldTemp, newTemp)...)
+	cmd.Dir = tmpDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// --no-index exits 1 when the files differ
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+			errMsg := strings.TrimSpace(stderr.String())
+			if errMsg != "" {
+				return "", fmt.Errorf("git diff failed: %s", errMsg)
+			}
+			return "", fmt.Errorf("git diff failed: %w", err)
+		}
+	}
+
+	return stdout.String(), nil
+}
+
+// GetRepoRoot returns the top-level directory of the repository containing path
+func GetRepoRoot(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	return repoRoot(workDir)
+}
+
+func repoRoot(workDir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = workDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository root: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
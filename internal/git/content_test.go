@@ -0,0 +1,92 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// runGitIn runs git in dir with a fixed identity
+func runGitIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=critica", "-c", "user.email=critica@example.com",
+		"-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// fullFileLines returns the prefixed lines of the single hunk of a full-file diff
+func fullFileLines(t *testing.T, diff string) []string {
+	t.Helper()
+	hunks, err := parser.ParseFileHunks(diff)
+	if err != nil {
+		t.Fatalf("ParseFileHunks: %v", err)
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("full-file diff has %d hunks, want 1:\n%s", len(hunks), diff)
+	}
+	var lines []string
+	for _, line := range hunks[0].Lines {
+		prefix := " "
+		switch line.Type {
+		case parser.LineAdded:
+			prefix = "+"
+		case parser.LineDeleted:
+			prefix = "-"
+		}
+		lines = append(lines, prefix+line.Content)
+	}
+	return lines
+}
+
+// TestGetFullFileDiffRename checks that a renamed file's old side is read from its old path
+func TestGetFullFileDiffRename(t *testing.T) {
+	dir := untrackedRepo(t, map[string]string{"old.txt": "one\ntwo\nthree\n"})
+	runGitIn(t, dir, "add", "-A")
+	runGitIn(t, dir, "commit", "-q", "-m", "base")
+	runGitIn(t, dir, "mv", "old.txt", "new.txt")
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("one\n2\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runGitIn(t, dir, "add", "-A")
+
+	diff, err := GetFullFileDiff(dir, "old.txt", "new.txt", DiffModeStaged, "", false, false)
+	if err != nil {
+		t.Fatalf("GetFullFileDiff: %v", err)
+	}
+	got := strings.Join(fullFileLines(t, diff), "\n")
+	if want := " one\n-two\n+2\n three"; got != want {
+		t.Errorf("full-file diff lines:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestGetFullFileDiffAgainstRef checks that a ref replaces HEAD as the old side of the
+// all-changes comparison, as --since does for the hunk view
+func TestGetFullFileDiffAgainstRef(t *testing.T) {
+	dir := untrackedRepo(t, map[string]string{"f.txt": "one\ntwo\n"})
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGitIn(t, dir, "add", "-A")
+	runGitIn(t, dir, "commit", "-q", "-m", "first")
+	write("one\n2\n")
+	runGitIn(t, dir, "commit", "-q", "-am", "second")
+	write("1\n2\n")
+
+	diff, err := GetFullFileDiff(dir, "f.txt", "f.txt", DiffModeAll, "HEAD~1", false, false)
+	if err != nil {
+		t.Fatalf("GetFullFileDiff: %v", err)
+	}
+	got := strings.Join(fullFileLines(t, diff), "\n")
+	if want := "-one\n-two\n+1\n+2"; got != want {
+		t.Errorf("full-file diff lines:\n%s\nwant:\n%s", got, want)
+	}
+}
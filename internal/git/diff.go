@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/danielss-dev/critica/internal/parser"
 )
 
 type DiffMode int
@@ -15,8 +17,48 @@ const (
 	DiffModeAll DiffMode = iota
 	DiffModeStaged
 	DiffModeUnstaged
+	// DiffModeUntracked shows only files git doesn't track yet, as new files
+	DiffModeUntracked
 )
 
+// Reverse, when true, swaps the two sides of every diff (git diff -R) to show what
+// reverting the changes would look like. Untracked files then appear as deleted.
+var Reverse bool
+
+// FunctionContext, when true, widens hunks to the whole functions they change (git diff -W)
+var FunctionContext bool
+
+// UntrackedMaxBytes is the size above which an untracked file is listed without its
+// content; 0 includes untracked files of any size
+var UntrackedMaxBytes int64
+
+// binarySniffLen is how much of a file is checked for NUL bytes, the same amount git checks
+const binarySniffLen = 8000
+
+// prefixArgs returns the git diff arguments giving the a/ and b/ prefixes explicitly, so
+// diff.noprefix and diff.mnemonicPrefix don't change the headers the parser reads
+func prefixArgs() []string {
+	return []string{"--src-prefix=a/", "--dst-prefix=b/"}
+}
+
+// reverseArgs returns the git diff arguments applying Reverse along with the prefixes.
+// git also swaps the a/ and b/ prefixes under -R, so they are swapped up front to keep
+// the usual headers.
+func reverseArgs() []string {
+	if !Reverse {
+		return prefixArgs()
+	}
+	return []string{"-R", "--src-prefix=b/", "--dst-prefix=a/"}
+}
+
+// contextArgs returns the git diff arguments for the context shown around changes
+func contextArgs() []string {
+	if FunctionContext {
+		return []string{"-U5", "--function-context"}
+	}
+	return []string{"-U5"}
+}
+
 // IsGitRepository checks if the given path is within a git repository
 func IsGitRepository(path string) bool {
 	// Get absolute path
@@ -44,41 +86,73 @@ func IsGitRepository(path string) bool {
 	return err == nil
 }
 
+// GetDiffAgainstRef retrieves the diff of the working tree against ref (git diff <ref>),
+// including untracked files, for the specified path
+func GetDiffAgainstRef(path, ref string) (string, error) {
+	return getDiffInternal([]string{path}, DiffModeAll, ref)
+}
+
+// GetDiffForPathsAgainstRef retrieves the diff of the working tree against ref, limited
+// to several paths of the same repository
+func GetDiffForPathsAgainstRef(paths []string, ref string) (string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	return getDiffInternal(paths, DiffModeAll, ref)
+}
+
 // GetDiff retrieves the git diff for the specified path
 func GetDiff(path string, staged bool) (string, error) {
 	if staged {
-		return getDiffInternal(path, DiffModeStaged)
+		return getDiffInternal([]string{path}, DiffModeStaged, "")
 	}
-	return getDiffInternal(path, DiffModeAll)
+	return getDiffInternal([]string{path}, DiffModeAll, "")
 }
 
 // GetDiffForMode retrieves the git diff for a specific diff mode
 func GetDiffForMode(path string, mode DiffMode) (string, error) {
-	return getDiffInternal(path, mode)
+	return getDiffInternal([]string{path}, mode, "")
 }
 
-func getDiffInternal(path string, mode DiffMode) (string, error) {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to get absolute path: %w", err)
+// GetDiffForPaths retrieves the git diff for a specific diff mode, limited to several
+// paths of the same repository. Each path is passed to git as a pathspec.
+func GetDiffForPaths(paths []string, mode DiffMode) (string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
 	}
+	return getDiffInternal(paths, mode, "")
+}
 
-	workDir := absPath
-	stat, err := os.Stat(absPath)
+// getDiffInternal runs git diff for mode, or against ref instead when ref is set
+func getDiffInternal(paths []string, mode DiffMode, ref string) (string, error) {
+	absPaths := make([]string, 0, len(paths))
+	for _, path := range paths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to get absolute path: %w", err)
+		}
+		absPaths = append(absPaths, absPath)
+	}
+
+	// Run git from the first path; the others are pathspecs in the same repository
+	workDir := absPaths[0]
+	stat, err := os.Stat(workDir)
 	if err == nil && !stat.IsDir() {
-		workDir = filepath.Dir(absPath)
+		workDir = filepath.Dir(workDir)
 	}
 
 	var allDiffs strings.Builder
 
-	regularDiff, err := runGitDiff(absPath, workDir, mode)
-	if err != nil {
-		return "", err
+	if mode != DiffModeUntracked {
+		regularDiff, err := runGitDiff(absPaths, workDir, mode, ref)
+		if err != nil {
+			return "", err
+		}
+		allDiffs.WriteString(regularDiff)
 	}
-	allDiffs.WriteString(regularDiff)
 
 	if shouldIncludeUntracked(mode) {
-		untrackedDiff, err := getUntrackedFilesDiff(workDir, absPath)
+		untrackedDiff, err := getUntrackedFilesDiff(workDir, absPaths)
 		if err == nil && untrackedDiff != "" {
 			if allDiffs.Len() > 0 {
 				allDiffs.WriteString("\n")
@@ -90,26 +164,28 @@ func getDiffInternal(path string, mode DiffMode) (string, error) {
 	return allDiffs.String(), nil
 }
 
-func runGitDiff(absPath, workDir string, mode DiffMode) (string, error) {
+func runGitDiff(absPaths []string, workDir string, mode DiffMode, ref string) (string, error) {
 	args := []string{"diff"}
 
-	switch mode {
-	case DiffModeStaged:
+	switch {
+	case ref != "":
+		args = append(args, ref)
+	case mode == DiffModeStaged:
 		args = append(args, "--staged")
-	case DiffModeAll:
+	case mode == DiffModeAll:
 		args = append(args, "HEAD")
-	case DiffModeUnstaged:
+	case mode == DiffModeUnstaged:
 		// git diff (no additional args) shows unstaged changes
 	default:
 		args = append(args, "HEAD")
 	}
 
-	args = append(args, "-U5")
+	args = append(args, contextArgs()...)
 	args = append(args, "--no-color")
+	args = append(args, reverseArgs()...)
 
-	if absPath != "." {
-		args = append(args, "--", absPath)
-	}
+	args = append(args, "--")
+	args = append(args, absPaths...)
 
 	cmd := exec.Command("git", args...)
 	cmd.Dir = workDir
@@ -136,13 +212,20 @@ func runGitDiff(absPath, workDir string, mode DiffMode) (string, error) {
 }
 
 func shouldIncludeUntracked(mode DiffMode) bool {
-	return mode == DiffModeAll || mode == DiffModeUnstaged
+	return mode == DiffModeAll || mode == DiffModeUnstaged || mode == DiffModeUntracked
 }
 
-func getUntrackedFilesDiff(workDir, filterPath string) (string, error) {
-	// Get list of untracked files
-	cmd := exec.Command("git", "ls-files", "--others", "--exclude-standard")
-	cmd.Dir = workDir
+func getUntrackedFilesDiff(workDir string, filterPaths []string) (string, error) {
+	// List untracked files from the repository root so paths match git diff headers,
+	// letting git apply the filter paths as pathspecs.
+	// -z disables path quoting so names with special characters can be read from disk
+	root, err := repoRoot(workDir)
+	if err != nil {
+		return "", err
+	}
+	args := append([]string{"ls-files", "-z", "--others", "--exclude-standard", "--"}, filterPaths...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
 
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
@@ -151,7 +234,7 @@ func getUntrackedFilesDiff(workDir, filterPath string) (string, error) {
 		return "", err
 	}
 
-	untrackedFiles := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	untrackedFiles := strings.Split(strings.TrimRight(stdout.String(), "\x00"), "\x00")
 	if len(untrackedFiles) == 0 || untrackedFiles[0] == "" {
 		return "", nil
 	}
@@ -163,44 +246,87 @@ func getUntrackedFilesDiff(workDir, filterPath string) (string, error) {
 			continue
 		}
 
-		// If a filter path is specified, check if this file matches
-		relPath, _ := filepath.Rel(workDir, filterPath)
-		// Normalize to forward slashes for comparison with git output (which always uses /)
-		relPath = filepath.ToSlash(relPath)
-		if relPath != "." && relPath != "" {
-			// Filter path is a subdirectory or file
-			if !strings.HasPrefix(file, relPath+"/") && file != relPath {
-				continue
-			}
+		fullPath := filepath.Join(root, file)
+		info, err := os.Stat(fullPath)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		// Normalize file path to forward slashes for git diff format
+		gitFilePath := filepath.ToSlash(file)
+		oldPath, newPath := "/dev/null", parser.QuotePath("b/"+gitFilePath)
+		if Reverse {
+			oldPath, newPath = parser.QuotePath("a/"+gitFilePath), "/dev/null"
+		}
+
+		// Generate diff format for new file, or a deleted one when reversed
+		result.WriteString(fmt.Sprintf("diff --git %s %s\n", parser.QuotePath("a/"+gitFilePath), parser.QuotePath("b/"+gitFilePath)))
+		if Reverse {
+			result.WriteString("deleted file mode 100644\n")
+		} else {
+			result.WriteString("new file mode 100644\n")
+		}
+		result.WriteString(parser.UntrackedFileHeader + "\n")
+		result.WriteString("index 0000000..0000000\n")
+
+		// Large files are listed without content rather than read into the diff
+		if UntrackedMaxBytes > 0 && info.Size() > UntrackedMaxBytes {
+			result.WriteString(parser.ContentOmittedHeader + "\n")
+			continue
 		}
 
-		// Read file content
-		fullPath := filepath.Join(workDir, file)
 		content, err := os.ReadFile(fullPath)
 		if err != nil {
+			result.WriteString(parser.ContentOmittedHeader + "\n")
+			continue
+		}
+		if isBinary(content) {
+			result.WriteString(fmt.Sprintf("Binary files %s and %s differ\n", oldPath, newPath))
 			continue
 		}
 
-		// Normalize file path to forward slashes for git diff format
-		gitFilePath := filepath.ToSlash(file)
+		// An empty file has no hunk, as in git's own diffs
+		if len(content) == 0 {
+			continue
+		}
 
-		// Generate diff format for new file
-		result.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", gitFilePath, gitFilePath))
-		result.WriteString("new file mode 100644\n")
-		result.WriteString("index 0000000..0000000\n")
-		result.WriteString("--- /dev/null\n")
-		result.WriteString(fmt.Sprintf("+++ b/%s\n", gitFilePath))
-		result.WriteString("@@ -0,0 +1,")
+		// A final newline terminates the last line rather than starting another one
+		text, hasFinalNewline := strings.CutSuffix(string(content), "\n")
+		lines := strings.Split(text, "\n")
 
-		lines := strings.Split(string(content), "\n")
-		result.WriteString(fmt.Sprintf("%d @@\n", len(lines)))
+		result.WriteString(fmt.Sprintf("--- %s\n", oldPath))
+		result.WriteString(fmt.Sprintf("+++ %s\n", newPath))
+		// Like git, a one-line range leaves out its count
+		lineRange := fmt.Sprintf("1,%d", len(lines))
+		if len(lines) == 1 {
+			lineRange = "1"
+		}
+		prefix := "+"
+		if Reverse {
+			prefix = "-"
+			result.WriteString(fmt.Sprintf("@@ -%s +0,0 @@\n", lineRange))
+		} else {
+			result.WriteString(fmt.Sprintf("@@ -0,0 +%s @@\n", lineRange))
+		}
 
 		for _, line := range lines {
-			result.WriteString("+")
+			result.WriteString(prefix)
 			result.WriteString(line)
 			result.WriteString("\n")
 		}
+		if !hasFinalNewline {
+			result.WriteString("\\ No newline at end of file\n")
+		}
 	}
 
 	return result.String(), nil
 }
+
+// isBinary reports whether content looks binary, using git's heuristic of a NUL byte
+// near the start of the file
+func isBinary(content []byte) bool {
+	if len(content) > binarySniffLen {
+		content = content[:binarySniffLen]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
@@ -0,0 +1,209 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// untrackedRepo creates a repository holding the given untracked files
+func untrackedRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// gitHunks returns the hunks of git's own diff of a new file, from the first "@@" on
+func gitHunks(t *testing.T, dir, name string) string {
+	t.Helper()
+	cmd := exec.Command("git", "diff", "--no-index", "--", "/dev/null", name)
+	cmd.Dir = dir
+	// --no-index exits 1 when the files differ
+	out, _ := cmd.Output()
+	_, hunks, found := strings.Cut(string(out), "\n@@")
+	if !found {
+		t.Fatalf("git diff --no-index printed no hunk for %s:\n%s", name, out)
+	}
+	return "@@" + hunks
+}
+
+// TestUntrackedFilesDiff checks the diff synthesized for untracked files against git's
+// own diff of a new file, with and without a final newline
+func TestUntrackedFilesDiff(t *testing.T) {
+	tests := []struct {
+		name, content string
+		want          []string
+		noNewline     bool
+	}{
+		{"final.txt", "one\ntwo\n", []string{"one", "two"}, false},
+		{"nofinal.txt", "one\ntwo", []string{"one", "two"}, true},
+		{"single.txt", "only", []string{"only"}, true},
+		{"blank.txt", "\n", []string{""}, false},
+	}
+
+	files := make(map[string]string)
+	for _, tt := range tests {
+		files[tt.name] = tt.content
+	}
+	dir := untrackedRepo(t, files)
+
+	diff, err := getUntrackedFilesDiff(dir, nil)
+	if err != nil {
+		t.Fatalf("getUntrackedFilesDiff: %v", err)
+	}
+	parsed, err := parser.ParseDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v\n%s", err, diff)
+	}
+	if len(parsed) != len(tests) {
+		t.Fatalf("got %d files, want %d:\n%s", len(parsed), len(tests), diff)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The hunk text matches what git prints for the same file
+			start := strings.Index(diff, "+++ b/"+tt.name+"\n")
+			if start < 0 {
+				t.Fatalf("no +++ line for %s:\n%s", tt.name, diff)
+			}
+			section := diff[start+len("+++ b/"+tt.name+"\n"):]
+			if next := strings.Index(section, "diff --git "); next >= 0 {
+				section = section[:next]
+			}
+			if want := gitHunks(t, dir, tt.name); section != want {
+				t.Errorf("hunk =\n%s\nwant (from git)\n%s", section, want)
+			}
+			if got := strings.Contains(section, "\\ No newline at end of file"); got != tt.noNewline {
+				t.Errorf("no-newline marker present = %v, want %v", got, tt.noNewline)
+			}
+
+			var file parser.FileDiff
+			for _, f := range parsed {
+				if f.NewPath == tt.name {
+					file = f
+				}
+			}
+			if !file.IsNew || !file.IsUntracked || len(file.Hunks) != 1 {
+				t.Fatalf("parsed %+v, want one hunk of a new untracked file", file)
+			}
+			lines := file.Hunks[0].Lines
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d", len(lines), len(tt.want))
+			}
+			for i, line := range lines {
+				if line.Type != parser.LineAdded || line.Content != tt.want[i] || line.NewLineNum != i+1 {
+					t.Errorf("line %d = %+v, want added %q at %d", i, line, tt.want[i], i+1)
+				}
+			}
+			if last := lines[len(lines)-1]; last.NoNewline != tt.noNewline {
+				t.Errorf("last line NoNewline = %v, want %v", last.NoNewline, tt.noNewline)
+			}
+		})
+	}
+}
+
+// TestUntrackedFilesDiffEmptyFile checks that an empty untracked file is listed without
+// a hunk, as git lists it
+func TestUntrackedFilesDiffEmptyFile(t *testing.T) {
+	dir := untrackedRepo(t, map[string]string{"empty.txt": ""})
+
+	diff, err := getUntrackedFilesDiff(dir, nil)
+	if err != nil {
+		t.Fatalf("getUntrackedFilesDiff: %v", err)
+	}
+	if strings.Contains(diff, "@@") || strings.Contains(diff, "---") {
+		t.Errorf("empty file has a hunk:\n%s", diff)
+	}
+	files, err := parser.ParseDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	if len(files) != 1 || files[0].NewPath != "empty.txt" || !files[0].IsNew || len(files[0].Hunks) != 0 {
+		t.Errorf("parsed %+v, want one new empty file", files)
+	}
+}
+
+// TestUntrackedFilesDiffReverse checks that --reverse shows untracked files as deleted,
+// keeping the no-newline marker
+func TestUntrackedFilesDiffReverse(t *testing.T) {
+	dir := untrackedRepo(t, map[string]string{"nofinal.txt": "one\ntwo"})
+	Reverse = true
+	defer func() { Reverse = false }()
+
+	diff, err := getUntrackedFilesDiff(dir, nil)
+	if err != nil {
+		t.Fatalf("getUntrackedFilesDiff: %v", err)
+	}
+	want := "diff --git a/nofinal.txt b/nofinal.txt\n" +
+		"deleted file mode 100644\n" +
+		parser.UntrackedFileHeader + "\n" +
+		"index 0000000..0000000\n" +
+		"--- a/nofinal.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1,2 +0,0 @@\n" +
+		"-one\n" +
+		"-two\n" +
+		"\\ No newline at end of file\n"
+	if diff != want {
+		t.Errorf("diff =\n%s\nwant\n%s", diff, want)
+	}
+}
+
+// TestUntrackedFilesDiffQuotedPaths checks that the headers of untracked files with
+// special characters in their names are quoted as git quotes them, with octal escapes
+// for bytes outside ASCII
+func TestUntrackedFilesDiffQuotedPaths(t *testing.T) {
+	names := []string{"ü.txt", `say "hi".txt`, `back\slash.txt`, "tab\there.txt", "sp ace.txt"}
+	files := make(map[string]string)
+	for _, name := range names {
+		files[name] = "content\n"
+	}
+	dir := untrackedRepo(t, files)
+
+	diff, err := getUntrackedFilesDiff(dir, nil)
+	if err != nil {
+		t.Fatalf("getUntrackedFilesDiff: %v", err)
+	}
+	for _, name := range names {
+		cmd := exec.Command("git", "-c", "core.quotePath=true", "diff", "--no-index", "--", "/dev/null", name)
+		cmd.Dir = dir
+		out, _ := cmd.Output()
+		header, _, _ := strings.Cut(string(out), "\n")
+		if !strings.HasPrefix(header, "diff --git ") {
+			t.Fatalf("git diff --no-index printed no header for %q:\n%s", name, out)
+		}
+		if !strings.Contains(diff, header+"\n") {
+			t.Errorf("no header %s for %q in:\n%s", header, name, diff)
+		}
+	}
+
+	parsed, err := parser.ParseDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	var got []string
+	for _, file := range parsed {
+		got = append(got, file.NewPath)
+	}
+	if want := []string{`back\slash.txt`, `say "hi".txt`, "sp ace.txt", "tab\there.txt", "ü.txt"}; !slices.Equal(got, want) {
+		t.Errorf("parsed paths = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DryRun, when set, makes StageAllFiles, CreateCommit and PushBranch write the git
+// command they would run to it instead of executing it
+var DryRun io.Writer
+
+// CommandString formats git arguments as a shell command line, quoting where needed
+func CommandString(args ...string) string {
+	parts := []string{"git"}
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'$`\\") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// skipForDryRun reports the command and returns true when dry-run mode is enabled
+func skipForDryRun(args ...string) bool {
+	if DryRun == nil {
+		return false
+	}
+	fmt.Fprintf(DryRun, "[dry run] %s\n", CommandString(args...))
+	return true
+}
@@ -0,0 +1,105 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// editMessageHint is appended to a message opened for editing; like git, lines starting
+// with '#' are dropped from the result
+const editMessageHint = `
+# Edit the commit message above. Lines starting with '#' are ignored,
+# and an empty message aborts the commit.
+`
+
+// Editor returns the editor git would use for a commit message in the repository
+// containing path: GIT_EDITOR, core.editor, VISUAL, EDITOR, then git's default
+func Editor(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	workDir := absPath
+	stat, err := os.Stat(absPath)
+	if err == nil && !stat.IsDir() {
+		workDir = filepath.Dir(absPath)
+	}
+
+	cmd := exec.Command("git", "var", "GIT_EDITOR")
+	cmd.Dir = workDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find an editor: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// EditMessage opens message in the user's editor and returns the edited text with
+// comment lines and surrounding whitespace removed. The terminal is attached so the
+// editor can run in it.
+func EditMessage(path, message string) (string, error) {
+	editor, err := Editor(path)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp("", "critica-commit-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(message + "\n" + editMessageHint)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := runEditor(editor, file.Name()); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited message: %w", err)
+	}
+
+	var kept []string
+	for _, line := range strings.Split(string(edited), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), nil
+}
+
+// EditFile opens file in the editor git would use for the repository containing path
+// (see Editor), with the terminal attached, and waits for it to exit
+func EditFile(path, file string) error {
+	editor, err := Editor(path)
+	if err != nil {
+		return err
+	}
+	return runEditor(editor, file)
+}
+
+// runEditor runs editor on file in the current terminal
+func runEditor(editor, file string) error {
+	// Run through the shell like git does, since the editor may include arguments
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, file)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEditFile checks that EditFile runs the editor git would use, arguments included
+func TestEditFile(t *testing.T) {
+	dir := untrackedRepo(t, nil)
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte("old\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_EDITOR", "sed -i -e s/old/new/")
+
+	if err := EditFile(dir, file); err != nil {
+		t.Fatalf("EditFile: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new\n" {
+		t.Errorf("edited file = %q, want %q", data, "new\n")
+	}
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// Version is a git release number such as 2.22.0
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// MinVersion is the oldest git critica is tested with; git branch --show-current
+// arrived in 2.22
+var MinVersion = Version{2, 22, 0}
+
+// ErrNotInstalled is returned when no git executable is found on PATH
+var ErrNotInstalled = errors.New("git is not installed or not on PATH: critica needs git to read diffs")
+
+var versionRegex = regexp.MustCompile(`^git version (\d+)\.(\d+)(?:\.(\d+))?`)
+
+var (
+	versionOnce   sync.Once
+	cachedVersion Version
+	cachedErr     error
+)
+
+// String formats the version as major.minor.patch
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Less reports whether v is an older release than other
+func (v Version) Less(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+// InstalledVersion runs git --version once and returns the parsed version, or
+// ErrNotInstalled when git can't be found
+func InstalledVersion() (Version, error) {
+	versionOnce.Do(func() {
+		cachedVersion, cachedErr = readVersion()
+	})
+	return cachedVersion, cachedErr
+}
+
+// readVersion runs git --version and parses its output, e.g. "git version 2.39.2 (Apple Git-143)"
+func readVersion() (Version, error) {
+	output, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return Version{}, ErrNotInstalled
+		}
+		return Version{}, fmt.Errorf("failed to run git --version: %w", err)
+	}
+
+	matches := versionRegex.FindSubmatch(output)
+	if matches == nil {
+		return Version{}, fmt.Errorf("unrecognized git --version output: %q", output)
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(string(matches[1]))
+	v.Minor, _ = strconv.Atoi(string(matches[2]))
+	if len(matches[3]) > 0 {
+		v.Patch, _ = strconv.Atoi(string(matches[3]))
+	}
+	return v, nil
+}
+
+// olderThan reports whether the installed git is known to be older than v
+func olderThan(v Version) bool {
+	installed, err := InstalledVersion()
+	return err == nil && installed.Less(v)
+}
@@ -0,0 +1,42 @@
+package parser
+
+import "sort"
+
+// ChangedLines returns the number of added and deleted lines in the file diff
+func (f FileDiff) ChangedLines() int {
+	changed := 0
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			if line.Type == LineAdded || line.Type == LineDeleted {
+				changed++
+			}
+		}
+	}
+	return changed
+}
+
+// KeepLargest returns the n files with the most changed lines, in their original order.
+// Files are returned unchanged when there are no more than n of them or n <= 0.
+func KeepLargest(files []FileDiff, n int) []FileDiff {
+	if n <= 0 || len(files) <= n {
+		return files
+	}
+
+	order := make([]int, len(files))
+	sizes := make([]int, len(files))
+	for i, file := range files {
+		order[i] = i
+		sizes[i] = file.ChangedLines()
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sizes[order[a]] > sizes[order[b]]
+	})
+	kept := order[:n]
+	sort.Ints(kept)
+
+	result := make([]FileDiff, 0, n)
+	for _, i := range kept {
+		result = append(result, files[i])
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package parser
+
+import "strings"
+
+// maxMoveCandidates bounds the deleted lines an added line is compared with. A line deleted
+// more often than this, such as a lone closing brace, doesn't start a moved run, which
+// keeps matching linear in files full of repeated lines; it can still extend a run that
+// started on a rarer line.
+const maxMoveCandidates = 16
+
+// linePos locates a line within a file's hunks
+type linePos struct {
+	hunk, line int
+}
+
+// MarkMovedLines returns file with Moved set on runs of deleted lines that reappear as a
+// run of added lines elsewhere in the same file, and on those added lines. A run only
+// counts as moved when it has at least minLines non-blank lines, so short, common lines
+// such as a lone closing brace are not mistaken for moves; minLines <= 0 disables
+// detection. Lines match when their content and line ending match, and an added run is
+// never matched with the deleted run right before it, which is a change in place (such as
+// a line ending conversion) rather than a move. The input is not modified: hunks are copied when any line is marked.
+func MarkMovedLines(file FileDiff, minLines int) FileDiff {
+	if minLines <= 0 {
+		return file
+	}
+
+	deletedRuns := changeRuns(file, LineDeleted)
+	addedRuns := changeRuns(file, LineAdded)
+	if len(deletedRuns) == 0 || len(addedRuns) == 0 {
+		return file
+	}
+
+	// Index every deleted line by content and line ending so matching added lines find
+	// their candidates
+	type lineKey struct {
+		content string
+		hasCR   bool
+	}
+	type runOffset struct {
+		run, offset int
+	}
+	key := func(pos linePos) lineKey {
+		line := file.Hunks[pos.hunk].Lines[pos.line]
+		return lineKey{line.Content, line.HasCR}
+	}
+	deletedIndex := make(map[lineKey][]runOffset)
+	for runIdx, run := range deletedRuns {
+		for offset, pos := range run {
+			if strings.TrimSpace(key(pos).content) == "" {
+				continue
+			}
+			deletedIndex[key(pos)] = append(deletedIndex[key(pos)], runOffset{runIdx, offset})
+		}
+	}
+
+	moved := make(map[linePos]bool)
+	content := func(pos linePos) string {
+		return file.Hunks[pos.hunk].Lines[pos.line].Content
+	}
+
+	for _, added := range addedRuns {
+		// The deleted run the added run directly replaces is not a move source
+		replaced := -1
+		for runIdx, deleted := range deletedRuns {
+			last := deleted[len(deleted)-1]
+			if last.hunk == added[0].hunk && last.line+1 == added[0].line {
+				replaced = runIdx
+				break
+			}
+		}
+
+		for start := 0; start < len(added); {
+			candidates := deletedIndex[key(added[start])]
+			if len(candidates) > maxMoveCandidates {
+				start++
+				continue
+			}
+
+			bestLen, bestRun, bestOffset := 0, 0, 0
+			for _, candidate := range candidates {
+				if candidate.run == replaced {
+					continue
+				}
+				deleted := deletedRuns[candidate.run]
+				length := 0
+				for start+length < len(added) && candidate.offset+length < len(deleted) &&
+					key(added[start+length]) == key(deleted[candidate.offset+length]) {
+					length++
+				}
+				if length > bestLen {
+					bestLen, bestRun, bestOffset = length, candidate.run, candidate.offset
+				}
+			}
+
+			if bestLen == 0 {
+				start++
+				continue
+			}
+
+			nonBlank := 0
+			for _, pos := range added[start : start+bestLen] {
+				if strings.TrimSpace(content(pos)) != "" {
+					nonBlank++
+				}
+			}
+			if nonBlank >= minLines {
+				for i := 0; i < bestLen; i++ {
+					moved[added[start+i]] = true
+					moved[deletedRuns[bestRun][bestOffset+i]] = true
+				}
+			}
+			start += bestLen
+		}
+	}
+
+	if len(moved) == 0 {
+		return file
+	}
+
+	hunks := make([]Hunk, len(file.Hunks))
+	for hunkIdx, hunk := range file.Hunks {
+		lines := make([]Line, len(hunk.Lines))
+		copy(lines, hunk.Lines)
+		for lineIdx := range lines {
+			if moved[linePos{hunkIdx, lineIdx}] {
+				lines[lineIdx].Moved = true
+			}
+		}
+		hunk.Lines = lines
+		hunks[hunkIdx] = hunk
+	}
+	file.Hunks = hunks
+	return file
+}
+
+// changeRuns returns the maximal runs of consecutive lines of the given type in each hunk
+func changeRuns(file FileDiff, lineType LineType) [][]linePos {
+	var runs [][]linePos
+	for hunkIdx, hunk := range file.Hunks {
+		var run []linePos
+		for lineIdx, line := range hunk.Lines {
+			if line.Type == lineType {
+				run = append(run, linePos{hunkIdx, lineIdx})
+				continue
+			}
+			if len(run) > 0 {
+				runs = append(runs, run)
+				run = nil
+			}
+		}
+		if len(run) > 0 {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// movedLines returns the prefixed lines of file marked as moved
+func movedLines(file FileDiff) []string {
+	var moved []string
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			if !line.Moved {
+				continue
+			}
+			prefix := "+"
+			if line.Type == LineDeleted {
+				prefix = "-"
+			}
+			moved = append(moved, prefix+line.Content)
+		}
+	}
+	return moved
+}
+
+// TestMarkMovedLines checks that blocks moved elsewhere in a file are marked, and that
+// blocks replaced in place are not, even when the content only differs in line endings
+func TestMarkMovedLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		hunks string
+		want  int
+	}{
+		{
+			name: "moved down",
+			hunks: "@@ -1,5 +1,2 @@\n-one\n-two\n-three\n keep\n keep too\n" +
+				"@@ -20,2 +17,5 @@\n end\n+one\n+two\n+three\n end too\n",
+			want: 6,
+		},
+		{
+			name:  "replaced in place",
+			hunks: "@@ -1,3 +1,3 @@\n-one\n-two\n-three\n+one\n+two\n+three\n",
+			want:  0,
+		},
+		{
+			name:  "line endings converted in place",
+			hunks: "@@ -1,3 +1,3 @@\n-one\r\n-two\r\n-three\r\n+one\n+two\n+three\n",
+			want:  0,
+		},
+		{
+			name: "moved after a frequent line",
+			hunks: "@@ -1,25 +1,1 @@\n" + strings.Repeat("-}\n", 20) + " keep\n-}\n-one\n-two\n-three\n" +
+				"@@ -40,2 +15,6 @@\n end\n+}\n+one\n+two\n+three\n end too\n",
+			want: 6,
+		},
+		{
+			name: "moved with line endings converted",
+			hunks: "@@ -1,5 +1,2 @@\n-one\r\n-two\r\n-three\r\n keep\n keep too\n" +
+				"@@ -20,2 +17,5 @@\n end\n+one\n+two\n+three\n end too\n",
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := "diff --git a/f.txt b/f.txt\n--- a/f.txt\n+++ b/f.txt\n" + tt.hunks
+			files, err := ParseDiff(diff)
+			if err != nil {
+				t.Fatalf("ParseDiff: %v", err)
+			}
+			moved := movedLines(MarkMovedLines(files[0], 3))
+			if len(moved) != tt.want {
+				t.Errorf("moved lines = %s, want %d of them", strings.Join(moved, ", "), tt.want)
+			}
+		})
+	}
+}
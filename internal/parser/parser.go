@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -22,8 +24,11 @@ const (
 type Line struct {
 	Type       LineType
 	Content    string
-	OldLineNum int // 0 if not applicable
-	NewLineNum int // 0 if not applicable
+	OldLineNum int  // 0 if not applicable
+	NewLineNum int  // 0 if not applicable
+	HasCR      bool // Line ended in \r (CRLF) before it was stripped from Content
+	Moved      bool // Part of a block that was moved within the file; set by MarkMovedLines
+	NoNewline  bool // Followed by "\ No newline at end of file"; the file side ends without one
 }
 
 // Hunk represents a chunk of changes in a file
@@ -32,7 +37,9 @@ type Hunk struct {
 	OldLines int
 	NewStart int
 	NewLines int
-	Lines    []Line
+	// Section is the text git prints after the hunk range, usually the enclosing function
+	Section string
+	Lines   []Line
 }
 
 // FileDiff represents all changes in a single file
@@ -42,175 +49,521 @@ type FileDiff struct {
 	IsNew     bool
 	IsDeleted bool
 	IsRenamed bool
+	// Similarity is a renamed file's similarity index from git, e.g. "83%"
+	Similarity string
+	// IsUntracked marks a file git doesn't track yet, synthesized as a new file by critica
+	IsUntracked bool
+	// IsBinary marks a file git reports as "Binary files ... differ", which has no hunks
+	IsBinary bool
+	// ContentOmitted marks a file listed without its content, e.g. an untracked file over
+	// the size limit
+	ContentOmitted bool
+	// OldBlob and NewBlob are the (usually abbreviated) object hashes from the index line,
+	// all zeros on the side where the file doesn't exist; empty when the diff has no index line
+	OldBlob string
+	NewBlob string
+	// Mode is the file mode, e.g. "100644", from the index line or, for new, deleted and
+	// mode-changed files, the mode header line (the new mode when it changed)
+	Mode string
+	// OldMode is the mode before a mode change, from the "old mode" line; empty when
+	// the mode didn't change
+	OldMode   string
 	Extension string
 	Hunks     []Hunk
 }
 
+const diffHeaderPrefix = "diff --git "
+
+// devNull is the path git diffs use for the missing side of an added or deleted file
+const devNull = "/dev/null"
+
+// UntrackedFileHeader is the extended header line critica adds to the diffs it synthesizes
+// for untracked files, so they can be told apart from new files in the index
+const UntrackedFileHeader = "untracked file"
+
+// ContentOmittedHeader is the extended header line critica adds to a file diff it lists
+// without content, such as an untracked file over the size limit
+const ContentOmittedHeader = "content omitted"
+
 var (
 	diffHeaderRegex = regexp.MustCompile(`^diff --git a/(.+) b/(.+)$`)
-	filePathRegex   = regexp.MustCompile(`^[+-]{3} (.+)$`)
+	indexLineRegex  = regexp.MustCompile(`^index ([0-9a-f]+)\.\.([0-9a-f]+)(?: ([0-7]+))?$`)
 	hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
 )
 
-// ParseDiff parses git diff output into structured FileDiff objects
+// ParseDiff parses git diff output into structured FileDiff objects. Input without any
+// file diffs yields an empty slice; only malformed hunk headers are reported as errors.
 func ParseDiff(diffOutput string) ([]FileDiff, error) {
-	if diffOutput == "" {
-		return []FileDiff{}, nil
-	}
+	return ParseReader(strings.NewReader(diffOutput))
+}
 
-	lines := strings.Split(diffOutput, "\n")
-	var files []FileDiff
-	var currentFile *FileDiff
-	var currentHunk *Hunk
-	var oldLineNum, newLineNum int
+// ParseReader parses git diff output like ParseDiff, reading it line by line from r so
+// the raw diff never has to be held in memory as a whole
+func ParseReader(r io.Reader) ([]FileDiff, error) {
+	return parseReader(r, nil)
+}
 
-	for i := 0; i < len(lines); i++ {
-		line := lines[i]
+// ParseDiffWithProgress parses git diff output like ParseDiff, calling onFile each time
+// a file diff is completed with the number of files parsed so far and the total number
+// of files in the input. onFile may be nil.
+func ParseDiffWithProgress(diffOutput string, onFile func(done, total int)) ([]FileDiff, error) {
+	if onFile == nil {
+		return ParseDiff(diffOutput)
+	}
 
-		// Check for diff header (start of new file)
-		if matches := diffHeaderRegex.FindStringSubmatch(line); matches != nil {
-			// Save previous hunk to previous file if exists
-			if currentHunk != nil && currentFile != nil {
-				currentFile.Hunks = append(currentFile.Hunks, *currentHunk)
-				currentHunk = nil
-			}
+	// Count the headers the parser will accept, so done reaches total at the end
+	total := 0
+	for line := range strings.Lines(diffOutput) {
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if _, ok := parseDiffHeader(line); ok {
+			total++
+		}
+	}
+	return parseReader(strings.NewReader(diffOutput), func(done int) {
+		onFile(done, total)
+	})
+}
 
-			// Save previous file if exists
-			if currentFile != nil {
-				files = append(files, *currentFile)
+// parseReader is the parsing loop shared by ParseReader and ParseDiffWithProgress,
+// calling onFile after each completed file when it isn't nil
+func parseReader(r io.Reader, onFile func(done int)) ([]FileDiff, error) {
+	p := &diffParser{onFile: onFile}
+	reader := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if parseErr := p.parseLine(lineNum, strings.TrimSuffix(line, "\n")); parseErr != nil {
+				return nil, parseErr
 			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read diff: %w", err)
+		}
+	}
+	return p.finish(), nil
+}
 
-			// Start new file
-			currentFile = &FileDiff{
-				OldPath: matches[1],
-				NewPath: matches[2],
-			}
-			currentFile.Extension = filepath.Ext(currentFile.NewPath)
-			continue
+// diffParser holds the state of a diff being parsed one line at a time
+type diffParser struct {
+	files       []FileDiff
+	currentFile *FileDiff
+	currentHunk *Hunk
+	oldLineNum  int
+	newLineNum  int
+	onFile      func(done int) // Called after each completed file; may be nil
+	// srcPrefix and dstPrefix are the current file's path prefixes, found in its header
+	srcPrefix string
+	dstPrefix string
+}
+
+// hunkComplete reports whether the current hunk has all the lines its header counts
+func (p *diffParser) hunkComplete() bool {
+	h := p.currentHunk
+	return p.oldLineNum >= h.OldStart+h.OldLines && p.newLineNum >= h.NewStart+h.NewLines
+}
+
+// closeFile completes the file being parsed, if any, with its last hunk
+func (p *diffParser) closeFile() {
+	if p.currentHunk != nil && p.currentFile != nil {
+		p.currentFile.Hunks = append(p.currentFile.Hunks, *p.currentHunk)
+	}
+	if p.currentFile != nil {
+		p.appendFile(*p.currentFile)
+	}
+	p.currentFile = nil
+	p.currentHunk = nil
+}
+
+// appendFile finishes a file diff
+func (p *diffParser) appendFile(file FileDiff) {
+	p.files = append(p.files, file)
+	if p.onFile != nil {
+		p.onFile(len(p.files))
+	}
+}
+
+// parseLine consumes one line of diff output, without its trailing newline. lineNum is
+// the 1-based line number used in error messages.
+func (p *diffParser) parseLine(lineNum int, line string) error {
+	// Strip CRLF line endings so they don't leak into paths or content
+	line, hasCR := strings.CutSuffix(line, "\r")
+
+	// Combined diffs of merge commits (git log -p --cc) compare more than two sides and
+	// have "@@@" hunk headers; they end the previous file and are skipped
+	if strings.HasPrefix(line, "diff --cc ") || strings.HasPrefix(line, "diff --combined ") {
+		p.closeFile()
+		return nil
+	}
+
+	// Check for diff header (start of new file)
+	if header, ok := parseDiffHeader(line); ok {
+		p.closeFile()
+
+		// Start new file
+		p.currentFile = &FileDiff{
+			OldPath: header.oldPath,
+			NewPath: header.newPath,
 		}
+		p.srcPrefix, p.dstPrefix = header.srcPrefix, header.dstPrefix
+		p.currentFile.Extension = filepath.Ext(p.currentFile.NewPath)
+		return nil
+	}
 
-		if currentFile == nil {
-			continue
+	currentFile := p.currentFile
+	if currentFile == nil {
+		return nil
+	}
+
+	// Check for file status indicators
+	if mode, ok := strings.CutPrefix(line, "new file mode "); ok {
+		currentFile.IsNew = true
+		currentFile.Mode = mode
+		return nil
+	}
+	if mode, ok := strings.CutPrefix(line, "deleted file mode "); ok {
+		currentFile.IsDeleted = true
+		currentFile.Mode = mode
+		return nil
+	}
+	if mode, ok := strings.CutPrefix(line, "old mode "); ok && p.currentHunk == nil {
+		currentFile.OldMode = mode
+		return nil
+	}
+	if mode, ok := strings.CutPrefix(line, "new mode "); ok && p.currentHunk == nil {
+		currentFile.Mode = mode
+		return nil
+	}
+	if matches := indexLineRegex.FindStringSubmatch(line); matches != nil && p.currentHunk == nil {
+		currentFile.OldBlob = matches[1]
+		currentFile.NewBlob = matches[2]
+		if matches[3] != "" {
+			currentFile.Mode = matches[3]
 		}
+		return nil
+	}
+	// The rename lines carry both paths without prefixes, whatever diff.noprefix and
+	// diff.mnemonicPrefix are set to
+	if oldPath, ok := strings.CutPrefix(line, "rename from "); ok && p.currentHunk == nil {
+		currentFile.IsRenamed = true
+		currentFile.OldPath = unquotePath(oldPath)
+		return nil
+	}
+	if newPath, ok := strings.CutPrefix(line, "rename to "); ok && p.currentHunk == nil {
+		currentFile.NewPath = unquotePath(newPath)
+		currentFile.Extension = filepath.Ext(currentFile.NewPath)
+		return nil
+	}
+	if line == UntrackedFileHeader && p.currentHunk == nil {
+		currentFile.IsUntracked = true
+		return nil
+	}
+	if line == ContentOmittedHeader && p.currentHunk == nil {
+		currentFile.ContentOmitted = true
+		return nil
+	}
+	if strings.HasPrefix(line, "Binary files") && p.currentHunk == nil {
+		currentFile.IsBinary = true
+		return nil
+	}
+
+	if similarity, ok := strings.CutPrefix(line, "similarity index "); ok && p.currentHunk == nil {
+		currentFile.Similarity = similarity
+		return nil
+	}
+
+	// Skip index lines, file mode lines
+	if strings.HasPrefix(line, "index ") ||
+		strings.HasPrefix(line, "Binary files") {
+		return nil
+	}
 
-		// Check for file status indicators
-		if strings.HasPrefix(line, "new file mode") {
+	// The --- and +++ lines are authoritative: /dev/null marks a new or deleted file even
+	// without a mode line, and their paths aren't ambiguous like the diff --git header's
+	if marker, path, ok := parseFilePathLine(line); ok && p.currentHunk == nil {
+		switch {
+		case marker == "---" && path == devNull:
 			currentFile.IsNew = true
-			continue
-		}
-		if strings.HasPrefix(line, "deleted file mode") {
+		case marker == "+++" && path == devNull:
 			currentFile.IsDeleted = true
-			continue
+		case currentFile.IsRenamed:
+			// The rename lines already gave both paths, and a header without prefixes
+			// may have been split at the wrong place
+		case marker == "---":
+			if oldPath, ok := strings.CutPrefix(path, p.srcPrefix); ok {
+				currentFile.OldPath = oldPath
+			}
+		default:
+			if newPath, ok := strings.CutPrefix(path, p.dstPrefix); ok {
+				currentFile.NewPath = newPath
+				currentFile.Extension = filepath.Ext(newPath)
+			}
 		}
-		if strings.HasPrefix(line, "rename from") {
-			currentFile.IsRenamed = true
-			continue
+		return nil
+	}
+
+	// Check for hunk header
+	if strings.HasPrefix(line, "@@") {
+		matches := hunkHeaderRegex.FindStringSubmatch(line)
+		if matches == nil {
+			return fmt.Errorf("malformed hunk header on line %d: %q", lineNum, line)
 		}
 
-		// Skip index lines, file mode lines
-		if strings.HasPrefix(line, "index ") ||
-			strings.HasPrefix(line, "Binary files") ||
-			strings.HasPrefix(line, "similarity index") ||
-			strings.HasPrefix(line, "rename to") {
-			continue
+		// Save previous hunk
+		if p.currentHunk != nil {
+			currentFile.Hunks = append(currentFile.Hunks, *p.currentHunk)
 		}
 
-		// Skip --- and +++ lines (we already have paths)
-		if matches := filePathRegex.FindStringSubmatch(line); matches != nil {
-			continue
+		// Parse hunk information
+		oldStart, _ := strconv.Atoi(matches[1])
+		oldLines := 1
+		if matches[2] != "" {
+			oldLines, _ = strconv.Atoi(matches[2])
+		}
+		newStart, _ := strconv.Atoi(matches[3])
+		newLines := 1
+		if matches[4] != "" {
+			newLines, _ = strconv.Atoi(matches[4])
 		}
 
-		// Check for hunk header
-		if matches := hunkHeaderRegex.FindStringSubmatch(line); matches != nil {
-			// Save previous hunk
-			if currentHunk != nil {
-				currentFile.Hunks = append(currentFile.Hunks, *currentHunk)
-			}
+		p.currentHunk = &Hunk{
+			OldStart: oldStart,
+			OldLines: oldLines,
+			NewStart: newStart,
+			NewLines: newLines,
+			Section:  strings.TrimPrefix(line[len(matches[0]):], " "),
+			Lines:    []Line{},
+		}
 
-			// Parse hunk information
-			oldStart, _ := strconv.Atoi(matches[1])
-			oldLines := 1
-			if matches[2] != "" {
-				oldLines, _ = strconv.Atoi(matches[2])
-			}
-			newStart, _ := strconv.Atoi(matches[3])
-			newLines := 1
-			if matches[4] != "" {
-				newLines, _ = strconv.Atoi(matches[4])
-			}
+		p.oldLineNum = oldStart
+		p.newLineNum = newStart
+		return nil
+	}
 
-			currentHunk = &Hunk{
-				OldStart: oldStart,
-				OldLines: oldLines,
-				NewStart: newStart,
-				NewLines: newLines,
-				Lines:    []Line{},
-			}
+	// Parse diff lines
+	currentHunk := p.currentHunk
+	if currentHunk == nil || len(line) == 0 {
+		return nil
+	}
 
-			oldLineNum = oldStart
-			newLineNum = newStart
-			continue
+	prefix := line[0]
+	if prefix != '\\' && p.hunkComplete() {
+		// Text after the last line of the hunk, such as the commit messages between
+		// the diffs of git log -p
+		return nil
+	}
+	content := ""
+	if len(line) > 1 {
+		content = line[1:]
+	}
+
+	switch prefix {
+	case '+':
+		currentHunk.Lines = append(currentHunk.Lines, Line{
+			Type:       LineAdded,
+			Content:    content,
+			OldLineNum: 0,
+			NewLineNum: p.newLineNum,
+			HasCR:      hasCR,
+		})
+		p.newLineNum++
+
+	case '-':
+		currentHunk.Lines = append(currentHunk.Lines, Line{
+			Type:       LineDeleted,
+			Content:    content,
+			OldLineNum: p.oldLineNum,
+			NewLineNum: 0,
+			HasCR:      hasCR,
+		})
+		p.oldLineNum++
+
+	case ' ':
+		currentHunk.Lines = append(currentHunk.Lines, Line{
+			Type:       LineUnchanged,
+			Content:    content,
+			OldLineNum: p.oldLineNum,
+			NewLineNum: p.newLineNum,
+			HasCR:      hasCR,
+		})
+		p.oldLineNum++
+		p.newLineNum++
+
+	case '\\':
+		// "\ No newline at end of file" applies to the line before it
+		if n := len(currentHunk.Lines); n > 0 {
+			currentHunk.Lines[n-1].NoNewline = true
 		}
+	}
+	return nil
+}
 
-		// Parse diff lines
-		if currentHunk != nil {
-			if len(line) == 0 {
-				continue
-			}
+// finish saves the last hunk and file and returns every parsed file diff
+func (p *diffParser) finish() []FileDiff {
+	p.closeFile()
 
-			prefix := line[0]
-			content := ""
-			if len(line) > 1 {
-				content = line[1:]
-			}
+	if p.files == nil {
+		return []FileDiff{}
+	}
+	return p.files
+}
 
-			switch prefix {
-			case '+':
-				currentHunk.Lines = append(currentHunk.Lines, Line{
-					Type:       LineAdded,
-					Content:    content,
-					OldLineNum: 0,
-					NewLineNum: newLineNum,
-				})
-				newLineNum++
-
-			case '-':
-				currentHunk.Lines = append(currentHunk.Lines, Line{
-					Type:       LineDeleted,
-					Content:    content,
-					OldLineNum: oldLineNum,
-					NewLineNum: 0,
-				})
-				oldLineNum++
-
-			case ' ':
-				currentHunk.Lines = append(currentHunk.Lines, Line{
-					Type:       LineUnchanged,
-					Content:    content,
-					OldLineNum: oldLineNum,
-					NewLineNum: newLineNum,
-				})
-				oldLineNum++
-				newLineNum++
-
-			case '\\':
-				// "\ No newline at end of file" - skip
-				continue
-			}
+// ParseFileHunks parses a diff of a single file and returns its hunks. It is used for
+// diffs generated outside the main diff, such as full-file context views, where the
+// file metadata is already known.
+func ParseFileHunks(diffOutput string) ([]Hunk, error) {
+	files, err := ParseDiff(diffOutput)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return []Hunk{}, nil
+	}
+	return files[0].Hunks, nil
+}
+
+// diffHeader holds the paths of a "diff --git" header line and the prefixes they had
+type diffHeader struct {
+	oldPath   string
+	newPath   string
+	srcPrefix string
+	dstPrefix string
+}
+
+// parseDiffHeader extracts the old and new paths from a "diff --git" header line.
+// Git wraps paths containing special characters in double quotes with C-style escapes
+// (including octal-escaped UTF-8 bytes), so either side may be quoted.
+func parseDiffHeader(line string) (diffHeader, bool) {
+	rest, ok := strings.CutPrefix(line, diffHeaderPrefix)
+	if !ok {
+		return diffHeader{}, false
+	}
+	if !strings.Contains(rest, `"`) {
+		if matches := diffHeaderRegex.FindStringSubmatch(line); matches != nil {
+			return diffHeader{matches[1], matches[2], "a/", "b/"}, true
+		}
+		return splitUnprefixedHeader(rest)
+	}
+
+	var oldPath, newPath string
+	if strings.HasPrefix(rest, `"`) {
+		quoted, remainder, ok := cutQuoted(rest)
+		if !ok {
+			return diffHeader{}, false
+		}
+		oldPath = quoted
+		newPath = strings.TrimPrefix(remainder, " ")
+	} else {
+		idx := strings.Index(rest, ` "`)
+		if idx < 0 {
+			return diffHeader{}, false
 		}
+		oldPath = rest[:idx]
+		newPath = rest[idx+1:]
 	}
 
-	// Save last hunk and file
-	if currentHunk != nil && currentFile != nil {
-		currentFile.Hunks = append(currentFile.Hunks, *currentHunk)
+	if strings.HasPrefix(newPath, `"`) {
+		quoted, remainder, ok := cutQuoted(newPath)
+		if !ok || remainder != "" {
+			return diffHeader{}, false
+		}
+		newPath = quoted
 	}
-	if currentFile != nil {
-		files = append(files, *currentFile)
+
+	return splitPrefixes(oldPath, newPath), true
+}
+
+// splitUnprefixedHeader splits the paths of a header without the usual a/ and b/
+// prefixes, as git writes with diff.noprefix, diff.mnemonicPrefix or custom prefixes.
+// Without prefixes a header is ambiguous when the paths differ and contain spaces; the
+// rename and ---/+++ lines that follow correct the paths then.
+func splitUnprefixedHeader(rest string) (diffHeader, bool) {
+	// The same path on both sides, as for any file that wasn't renamed
+	if mid := len(rest) / 2; len(rest)%2 == 1 && rest[mid] == ' ' && rest[:mid] == rest[mid+1:] {
+		return splitPrefixes(rest[:mid], rest[mid+1:]), true
 	}
 
-	if len(files) == 0 {
-		return nil, fmt.Errorf("no diff data parsed")
+	// Prefixed paths, such as the i/ and w/ of diff.mnemonicPrefix
+	first := -1
+	for i := 0; i < len(rest); i++ {
+		if rest[i] != ' ' {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		if header := splitPrefixes(rest[:i], rest[i+1:]); header.srcPrefix != "" {
+			return header, true
+		}
+	}
+	if first < 0 {
+		return diffHeader{}, false
+	}
+	return diffHeader{oldPath: rest[:first], newPath: rest[first+1:]}, true
+}
+
+// splitPrefixes strips the prefixes from a header's paths: a/ and b/, or any first
+// directories that differ in front of otherwise equal paths. Paths that are equal or
+// don't share a remainder are taken to have no prefixes.
+func splitPrefixes(oldPath, newPath string) diffHeader {
+	if oldRest, ok := strings.CutPrefix(oldPath, "a/"); ok {
+		if newRest, ok := strings.CutPrefix(newPath, "b/"); ok {
+			return diffHeader{oldRest, newRest, "a/", "b/"}
+		}
 	}
+	if oldPath != newPath {
+		oldIdx, newIdx := strings.Index(oldPath, "/"), strings.Index(newPath, "/")
+		if oldIdx > 0 && newIdx > 0 && oldPath[oldIdx+1:] == newPath[newIdx+1:] && oldPath[oldIdx+1:] != "" {
+			return diffHeader{oldPath[oldIdx+1:], newPath[newIdx+1:], oldPath[:oldIdx+1], newPath[:newIdx+1]}
+		}
+	}
+	return diffHeader{oldPath: oldPath, newPath: newPath}
+}
+
+// unquotePath returns a path from an extended header line, unquoting it when git quoted it
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, _, ok := cutQuoted(path); ok {
+			return unquoted
+		}
+	}
+	return path
+}
+
+// parseFilePathLine splits a "--- a/path" or "+++ b/path" line into its marker and path,
+// unquoting the path and dropping the tab git appends to paths containing spaces
+func parseFilePathLine(line string) (string, string, bool) {
+	marker, path, ok := strings.Cut(line, " ")
+	if !ok || (marker != "---" && marker != "+++") || path == "" {
+		return "", "", false
+	}
+
+	if strings.HasPrefix(path, `"`) {
+		unquoted, _, ok := cutQuoted(path)
+		if !ok {
+			return "", "", false
+		}
+		return marker, unquoted, true
+	}
+	return marker, strings.TrimSuffix(path, "\t"), true
+}
 
-	return files, nil
+// cutQuoted unquotes the C-style quoted string at the start of s and returns it along
+// with the text following the closing quote
+func cutQuoted(s string) (string, string, bool) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			unquoted, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", false
+			}
+			return unquoted, s[i+1:], true
+		}
+	}
+	return "", "", false
 }
@@ -0,0 +1,331 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// readFixture returns the contents of a file in testdata
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// linesOf returns the content of every line of a file's hunks
+func linesOf(file FileDiff) []string {
+	var lines []string
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			lines = append(lines, line.Content)
+		}
+	}
+	return lines
+}
+
+// TestParseCombinedDiff checks that the combined diff of a merge commit in git log -p --cc
+// output is skipped rather than failing the parse, and that the commit messages between
+// the diffs don't end up in the hunks
+func TestParseCombinedDiff(t *testing.T) {
+	files, err := ParseDiff(readFixture(t, "log-cc-merge.diff"))
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+
+	want := []struct {
+		path  string
+		lines []string
+	}{
+		{"g.txt", []string{"hi"}},
+		{"f.txt", []string{"main", "a", "b", "c"}},
+		{"o.txt", []string{"one", "two"}},
+		{"f.txt", []string{"a", "b", "c", "side"}},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("got %d files, want %d", len(files), len(want))
+	}
+	for i, w := range want {
+		if files[i].NewPath != w.path {
+			t.Errorf("file %d: path %q, want %q", i, files[i].NewPath, w.path)
+		}
+		if got := linesOf(files[i]); !slices.Equal(got, w.lines) {
+			t.Errorf("file %d (%s): lines %q, want %q", i, w.path, got, w.lines)
+		}
+	}
+}
+
+func TestParseQuotedAndSpacedPaths(t *testing.T) {
+	files, err := ParseDiff(readFixture(t, "quoted-paths.diff"))
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+
+	want := []string{"sp dir.txt", "ü.txt", `say "hi".txt`}
+	if len(files) != len(want) {
+		t.Fatalf("got %d files, want %d", len(files), len(want))
+	}
+	for i, path := range want {
+		if files[i].OldPath != path || files[i].NewPath != path {
+			t.Errorf("file %d: paths %q -> %q, want %q", i, files[i].OldPath, files[i].NewPath, path)
+		}
+		if files[i].Extension != ".txt" {
+			t.Errorf("file %d: extension %q, want .txt", i, files[i].Extension)
+		}
+	}
+}
+
+func TestParseFilePathLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		marker string
+		path   string
+		ok     bool
+	}{
+		{"--- a/main.go", "---", "a/main.go", true},
+		{"+++ b/sp dir.txt\t", "+++", "b/sp dir.txt", true},
+		{`+++ "b/\303\274.txt"`, "+++", "b/ü.txt", true},
+		{`--- "a/say \"hi\".txt"`, "---", `a/say "hi".txt`, true},
+		{"--- /dev/null", "---", "/dev/null", true},
+		{`+++ "b/unterminated`, "", "", false},
+		{"-- a/main.go", "", "", false},
+		{"+++ ", "", "", false},
+	}
+	for _, tt := range tests {
+		marker, path, ok := parseFilePathLine(tt.line)
+		if marker != tt.marker || path != tt.path || ok != tt.ok {
+			t.Errorf("parseFilePathLine(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.line, marker, path, ok, tt.marker, tt.path, tt.ok)
+		}
+	}
+}
+
+// TestParseWithoutModeLines checks that /dev/null in the ---/+++ lines marks new and
+// deleted files when the diff has no "new file mode" or "deleted file mode" lines
+func TestParseWithoutModeLines(t *testing.T) {
+	files, err := ParseDiff(readFixture(t, "no-mode-lines.diff"))
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+
+	if !files[0].IsNew || files[0].IsDeleted || files[0].NewPath != "new.txt" {
+		t.Errorf("new.txt: IsNew=%v IsDeleted=%v path %q", files[0].IsNew, files[0].IsDeleted, files[0].NewPath)
+	}
+	if !files[1].IsDeleted || files[1].IsNew || files[1].OldPath != "gone.txt" {
+		t.Errorf("gone.txt: IsNew=%v IsDeleted=%v path %q", files[1].IsNew, files[1].IsDeleted, files[1].OldPath)
+	}
+}
+
+// TestParseCRLF checks that CRLF line endings, whether on every line of the diff or only
+// on the content of a file with Windows line endings, don't leak into paths or content
+func TestParseCRLF(t *testing.T) {
+	const diff = "diff --git a/dir/win.txt b/dir/win.txt\n" +
+		"index 1111111..2222222 100644\n" +
+		"--- a/dir/win.txt\n" +
+		"+++ b/dir/win.txt\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" keep\r\n" +
+		"-old\r\n" +
+		"+new\r\n" +
+		" tail\r\n" +
+		"diff --git a/added.txt b/added.txt\n" +
+		"new file mode 100644\n" +
+		"index 0000000..3333333\n" +
+		"--- /dev/null\n" +
+		"+++ b/added.txt\n" +
+		"@@ -0,0 +1 @@\n" +
+		"+only\r\n"
+
+	tests := []struct {
+		name string
+		diff string
+	}{
+		{"crlf content", diff},
+		{"crlf everywhere", strings.ReplaceAll(strings.ReplaceAll(diff, "\r\n", "\n"), "\n", "\r\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := ParseDiff(tt.diff)
+			if err != nil {
+				t.Fatalf("ParseDiff: %v", err)
+			}
+			if len(files) != 2 {
+				t.Fatalf("got %d files, want 2", len(files))
+			}
+
+			wantPaths := [][2]string{{"dir/win.txt", "dir/win.txt"}, {"added.txt", "added.txt"}}
+			wantLines := [][]string{{"keep", "old", "new", "tail"}, {"only"}}
+			for i, file := range files {
+				if file.OldPath != wantPaths[i][0] || file.NewPath != wantPaths[i][1] {
+					t.Errorf("file %d paths = %q, %q, want %q, %q", i, file.OldPath, file.NewPath, wantPaths[i][0], wantPaths[i][1])
+				}
+				if file.Extension != ".txt" {
+					t.Errorf("file %d extension = %q, want .txt", i, file.Extension)
+				}
+				if got := linesOf(file); !slices.Equal(got, wantLines[i]) {
+					t.Errorf("file %d lines = %q, want %q", i, got, wantLines[i])
+				}
+				for _, hunk := range file.Hunks {
+					for _, line := range hunk.Lines {
+						if !line.HasCR {
+							t.Errorf("file %d line %q lost its HasCR flag", i, line.Content)
+						}
+					}
+				}
+			}
+			if !files[1].IsNew {
+				t.Error("added.txt isn't marked as new")
+			}
+		})
+	}
+}
+
+// TestParsePrefixes checks that the same change parses to the same paths and lines
+// whatever prefixes git writes: the default a/ and b/, none with --no-prefix, custom
+// --src-prefix and --dst-prefix, and diff.mnemonicPrefix's c/ and i/. The change covers
+// paths with spaces, where a header without prefixes is ambiguous.
+func TestParsePrefixes(t *testing.T) {
+	repo := newGitRepo(t)
+	if err := os.MkdirAll(filepath.Join(repo.dir, "sub dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	repo.write("plain.go", "package main\n")
+	repo.write("sub dir/sp ace.txt", "one\ntwo\n")
+	repo.write("old name.txt", "alpha\nbeta\ngamma\ndelta\n")
+	repo.write("gone.txt", "bye\n")
+	repo.git("add", "-A")
+	repo.git("commit", "-q", "-m", "base")
+
+	repo.write("plain.go", "package main\n\nfunc main() {}\n")
+	repo.write("sub dir/sp ace.txt", "one\n2\n")
+	repo.remove("old name.txt")
+	repo.write("new name.txt", "alpha\nbeta\ngamma\ndelta!\n")
+	repo.remove("gone.txt")
+	repo.write("new file.txt", "hello\n")
+	repo.git("add", "-A")
+
+	want := []struct{ oldPath, newPath string }{
+		{"gone.txt", "gone.txt"},
+		{"new file.txt", "new file.txt"},
+		{"old name.txt", "new name.txt"},
+		{"plain.go", "plain.go"},
+		{"sub dir/sp ace.txt", "sub dir/sp ace.txt"},
+	}
+	parse := func(config []string, args ...string) []FileDiff {
+		t.Helper()
+		diff := repo.git(append(append(config, "diff", "--cached", "-M"), args...)...)
+		files, err := ParseDiff(diff)
+		if err != nil {
+			t.Fatalf("ParseDiff: %v\n%s", err, diff)
+		}
+		return files
+	}
+	base := parse(nil, "--src-prefix=a/", "--dst-prefix=b/")
+
+	variants := []struct {
+		name   string
+		config []string
+		args   []string
+	}{
+		{"default", nil, []string{"--src-prefix=a/", "--dst-prefix=b/"}},
+		{"no prefix", nil, []string{"--no-prefix"}},
+		{"custom prefixes", nil, []string{"--src-prefix=old/", "--dst-prefix=new/"}},
+		{"mnemonic prefixes", []string{"-c", "diff.mnemonicPrefix=true"}, nil},
+	}
+	for _, v := range variants {
+		t.Run(v.name, func(t *testing.T) {
+			files := parse(v.config, v.args...)
+			if len(files) != len(want) {
+				t.Fatalf("got %d files, want %d", len(files), len(want))
+			}
+			for i, file := range files {
+				if file.OldPath != want[i].oldPath || file.NewPath != want[i].newPath {
+					t.Errorf("file %d paths = %q, %q, want %q, %q", i, file.OldPath, file.NewPath, want[i].oldPath, want[i].newPath)
+				}
+				if got, wantLines := linesOf(file), linesOf(base[i]); !slices.Equal(got, wantLines) {
+					t.Errorf("%s lines = %q, want %q", file.NewPath, got, wantLines)
+				}
+				if file.IsNew != base[i].IsNew || file.IsDeleted != base[i].IsDeleted || file.IsRenamed != base[i].IsRenamed {
+					t.Errorf("%s status differs from the default prefixes", file.NewPath)
+				}
+			}
+		})
+	}
+}
+
+// TestParseDiffHeader checks the paths and prefixes split from diff --git headers
+func TestParseDiffHeader(t *testing.T) {
+	tests := []struct {
+		line string
+		want diffHeader
+	}{
+		{"diff --git a/x.go b/x.go", diffHeader{"x.go", "x.go", "a/", "b/"}},
+		{"diff --git a/sp ace.go b/sp ace.go", diffHeader{"sp ace.go", "sp ace.go", "a/", "b/"}},
+		{"diff --git x.go x.go", diffHeader{"x.go", "x.go", "", ""}},
+		{"diff --git dir/sp ace.go dir/sp ace.go", diffHeader{"dir/sp ace.go", "dir/sp ace.go", "", ""}},
+		{"diff --git old/x.go new/x.go", diffHeader{"x.go", "x.go", "old/", "new/"}},
+		{"diff --git i/sp ace.go w/sp ace.go", diffHeader{"sp ace.go", "sp ace.go", "i/", "w/"}},
+		{"diff --git one.go two.go", diffHeader{"one.go", "two.go", "", ""}},
+		{`diff --git "a/\303\274.txt" "b/\303\274.txt"`, diffHeader{"ü.txt", "ü.txt", "a/", "b/"}},
+		{`diff --git "\303\274.txt" "\303\274.txt"`, diffHeader{"ü.txt", "ü.txt", "", ""}},
+	}
+	for _, tt := range tests {
+		got, ok := parseDiffHeader(tt.line)
+		if !ok || got != tt.want {
+			t.Errorf("parseDiffHeader(%q) = %+v, %v, want %+v", tt.line, got, ok, tt.want)
+		}
+	}
+}
+
+// TestParseDiffWithProgress checks that progress counts only the file diffs that parse,
+// so it ends at 100% even when the input has combined diffs or malformed headers, and
+// that the result matches ParseDiff's
+func TestParseDiffWithProgress(t *testing.T) {
+	inputs := map[string]string{
+		"merge":     readFixture(t, "log-cc-merge.diff"),
+		"crlf":      strings.ReplaceAll(readFixture(t, "quoted-paths.diff"), "\n", "\r\n"),
+		"malformed": "diff --git nospace\n" + readFixture(t, "no-mode-lines.diff"),
+		"empty":     "",
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			var calls [][2]int
+			files, err := ParseDiffWithProgress(input, func(done, total int) {
+				calls = append(calls, [2]int{done, total})
+			})
+			if err != nil {
+				t.Fatalf("ParseDiffWithProgress: %v", err)
+			}
+
+			want, err := ParseDiff(input)
+			if err != nil {
+				t.Fatalf("ParseDiff: %v", err)
+			}
+			if len(files) != len(want) {
+				t.Fatalf("got %d files, ParseDiff got %d", len(files), len(want))
+			}
+			for i := range files {
+				if files[i].NewPath != want[i].NewPath || !slices.Equal(linesOf(files[i]), linesOf(want[i])) {
+					t.Errorf("file %d differs from ParseDiff's", i)
+				}
+			}
+
+			if len(calls) != len(files) {
+				t.Fatalf("got %d progress calls for %d files", len(calls), len(files))
+			}
+			for i, call := range calls {
+				if call != [2]int{i + 1, len(files)} {
+					t.Errorf("progress call %d = %d/%d, want %d/%d", i, call[0], call[1], i+1, len(files))
+				}
+			}
+		})
+	}
+}
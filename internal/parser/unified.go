@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// noNewlineMarker follows a diff line whose file side doesn't end in a newline
+const noNewlineMarker = `\ No newline at end of file`
+
+// FilesToUnified serializes file diffs back to git diff output, the inverse of ParseDiff
+func FilesToUnified(files []FileDiff) string {
+	var b strings.Builder
+	for _, file := range files {
+		b.WriteString(file.Unified())
+	}
+	return b.String()
+}
+
+// Unified serializes the file diff back to git's unified diff format, so it can be
+// written out as a patch for git apply or piped to other tools. Hunk header counts are
+// taken from the hunk lines, which keeps them right for hunks that were filtered or
+// edited after parsing.
+func (f FileDiff) Unified() string {
+	var b strings.Builder
+
+	oldName, newName := "a/"+f.OldPath, "b/"+f.NewPath
+	fmt.Fprintf(&b, "%s%s %s\n", diffHeaderPrefix, QuotePath(oldName), QuotePath(newName))
+
+	mode := f.Mode
+	if mode == "" {
+		mode = "100644"
+	}
+	modeChanged := f.OldMode != "" && f.OldMode != f.Mode
+	switch {
+	case f.IsNew:
+		fmt.Fprintf(&b, "new file mode %s\n", mode)
+		oldName = devNull
+	case f.IsDeleted:
+		fmt.Fprintf(&b, "deleted file mode %s\n", mode)
+		newName = devNull
+	case modeChanged:
+		fmt.Fprintf(&b, "old mode %s\nnew mode %s\n", f.OldMode, mode)
+	}
+	if f.IsRenamed {
+		if f.Similarity != "" {
+			fmt.Fprintf(&b, "similarity index %s\n", f.Similarity)
+		}
+		fmt.Fprintf(&b, "rename from %s\nrename to %s\n", QuotePath(f.OldPath), QuotePath(f.NewPath))
+	}
+	if f.IsUntracked {
+		b.WriteString(UntrackedFileHeader + "\n")
+	}
+	if f.OldBlob != "" && f.NewBlob != "" {
+		fmt.Fprintf(&b, "index %s..%s", f.OldBlob, f.NewBlob)
+		if !f.IsNew && !f.IsDeleted && !modeChanged && f.Mode != "" {
+			fmt.Fprintf(&b, " %s", f.Mode)
+		}
+		b.WriteString("\n")
+	}
+	if f.ContentOmitted {
+		b.WriteString(ContentOmittedHeader + "\n")
+		return b.String()
+	}
+	if f.IsBinary {
+		fmt.Fprintf(&b, "Binary files %s and %s differ\n", QuotePath(oldName), QuotePath(newName))
+		return b.String()
+	}
+	if len(f.Hunks) == 0 {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", filePathField(oldName), filePathField(newName))
+	for _, hunk := range f.Hunks {
+		writeHunk(&b, hunk)
+	}
+	return b.String()
+}
+
+// writeHunk writes a hunk header with counts taken from its lines, followed by the lines
+func writeHunk(b *strings.Builder, hunk Hunk) {
+	oldLines, newLines := 0, 0
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case LineAdded:
+			newLines++
+		case LineDeleted:
+			oldLines++
+		default:
+			oldLines++
+			newLines++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@", hunkRange(hunk.OldStart, oldLines), hunkRange(hunk.NewStart, newLines))
+	if hunk.Section != "" {
+		b.WriteString(" " + hunk.Section)
+	}
+	b.WriteString("\n")
+
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case LineAdded:
+			b.WriteByte('+')
+		case LineDeleted:
+			b.WriteByte('-')
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(line.Content)
+		if line.HasCR {
+			b.WriteByte('\r')
+		}
+		b.WriteByte('\n')
+		if line.NoNewline {
+			b.WriteString(noNewlineMarker + "\n")
+		}
+	}
+}
+
+// hunkRange formats one side of a hunk header; git leaves out a count of one
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// filePathField formats a path for the --- and +++ lines, where git ends names that
+// contain a space with a tab
+func filePathField(path string) string {
+	quoted := QuotePath(path)
+	if strings.Contains(quoted, " ") {
+		return quoted + "\t"
+	}
+	return quoted
+}
+
+// QuotePath quotes a path the way git does with core.quotePath, which parseDiffHeader and
+// parseFilePathLine undo. Paths with quotes, backslashes, control characters or bytes
+// outside ASCII are wrapped in double quotes, with C escapes for the common control
+// characters and octal escapes for other bytes, so "ü.txt" becomes "\303\274.txt".
+func QuotePath(path string) string {
+	needsQuotes := false
+	for i := 0; i < len(path); i++ {
+		if c := path[i]; c < 0x20 || c == '"' || c == '\\' || c >= 0x7f {
+			needsQuotes = true
+			break
+		}
+	}
+	if !needsQuotes {
+		return path
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\a':
+			b.WriteString(`\a`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\v':
+			b.WriteString(`\v`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if c < 0x20 || c >= 0x7f {
+				fmt.Fprintf(&b, `\%03o`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
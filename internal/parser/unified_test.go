@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gitRepo is a temporary repository for round-trip tests
+type gitRepo struct {
+	t   *testing.T
+	dir string
+}
+
+// newGitRepo creates an empty repository, skipping the test when git isn't installed
+func newGitRepo(t *testing.T) *gitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := &gitRepo{t: t, dir: t.TempDir()}
+	repo.git("init", "-q")
+	return repo
+}
+
+// git runs git in the repository with a fixed identity and returns its output
+func (r *gitRepo) git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=critica", "-c", "user.email=critica@example.com",
+		"-c", "commit.gpgsign=false", "-c", "core.quotePath=true"}, args...)...)
+	cmd.Dir = r.dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// write creates or replaces a file in the repository
+func (r *gitRepo) write(name, content string) {
+	r.t.Helper()
+	if err := os.WriteFile(filepath.Join(r.dir, name), []byte(content), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// remove deletes a file from the working tree
+func (r *gitRepo) remove(name string) {
+	r.t.Helper()
+	if err := os.Remove(filepath.Join(r.dir, name)); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+// checkApplies runs git apply --check on patch against the working tree
+func (r *gitRepo) checkApplies(patch string, args ...string) {
+	r.t.Helper()
+	path := filepath.Join(r.t.TempDir(), "export.patch")
+	if err := os.WriteFile(path, []byte(patch), 0o644); err != nil {
+		r.t.Fatal(err)
+	}
+	r.git(append(append([]string{"apply", "--check"}, args...), path)...)
+}
+
+// numberedLines returns "line 1\n" through "line n\n"
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	return b.String()
+}
+
+// commitChanges commits a base version and a change covering the cases a serialized
+// patch has to get right, and returns the diff between them
+func commitChanges(r *gitRepo) string {
+	base := numberedLines(40)
+	r.write("multi.txt", base)
+	r.write("old name.txt", "alpha\nbeta\ngamma\ndelta\nepsilon\n")
+	r.write("noeol.txt", "first\nlast without newline")
+	r.write("gone.txt", "bye\n")
+	r.git("add", "-A")
+	r.git("commit", "-q", "-m", "base")
+
+	// Two hunks far apart in one file
+	lines := strings.SplitAfter(base, "\n")
+	lines[2] = "changed near the top\n"
+	lines[35] = "changed near the bottom\n"
+	r.write("multi.txt", strings.Join(lines, ""))
+	// A rename with a change
+	r.remove("old name.txt")
+	r.write("new name.txt", "alpha\nbeta\ngamma\ndelta\nepsilon!\n")
+	// The last line changes and still has no newline
+	r.write("noeol.txt", "first\nnew last without newline")
+	r.remove("gone.txt")
+	r.write("ü.txt", "new file\n")
+	r.git("add", "-A")
+	r.git("commit", "-q", "-m", "change")
+
+	return r.git("diff", "-M", "--src-prefix=a/", "--dst-prefix=b/", "HEAD~1", "HEAD")
+}
+
+// TestUnifiedRoundTrip checks that serializing a parsed diff gives a patch git applies,
+// and that parsing and serializing the patch again doesn't change it
+func TestUnifiedRoundTrip(t *testing.T) {
+	repo := newGitRepo(t)
+	diff := commitChanges(repo)
+
+	files, err := ParseDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	patch := FilesToUnified(files)
+
+	reparsed, err := ParseDiff(patch)
+	if err != nil {
+		t.Fatalf("ParseDiff of the serialized patch: %v", err)
+	}
+	if again := FilesToUnified(reparsed); again != patch {
+		t.Errorf("serializing the parsed patch changed it:\nfirst:\n%s\nsecond:\n%s", patch, again)
+	}
+
+	// The patch applies to the base version and, reversed, to the changed one
+	repo.checkApplies(patch, "-R")
+	repo.git("checkout", "-q", "HEAD~1")
+	repo.checkApplies(patch)
+}
+
+// TestUnifiedModeChange checks that mode changes, with and without a content change,
+// serialize to the old mode and new mode lines git writes and apply again
+func TestUnifiedModeChange(t *testing.T) {
+	repo := newGitRepo(t)
+	repo.write("run.sh", "echo run\n")
+	repo.write("edit.sh", "echo edit\n")
+	repo.git("add", "-A")
+	repo.git("commit", "-q", "-m", "base")
+
+	repo.write("edit.sh", "echo edited\n")
+	for _, name := range []string{"run.sh", "edit.sh"} {
+		if err := os.Chmod(filepath.Join(repo.dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	diff := repo.git("diff", "--src-prefix=a/", "--dst-prefix=b/")
+
+	files, err := ParseDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("parsed %d files, want 2", len(files))
+	}
+	for _, file := range files {
+		if file.OldMode != "100644" || file.Mode != "100755" {
+			t.Errorf("%s: modes %q -> %q, want 100644 -> 100755", file.NewPath, file.OldMode, file.Mode)
+		}
+	}
+	patch := FilesToUnified(files)
+	if patch != diff {
+		t.Errorf("serialized patch differs from git diff:\ngot:\n%s\nwant:\n%s", patch, diff)
+	}
+
+	repo.checkApplies(patch, "-R")
+	repo.git("checkout", "-q", "--", ".")
+	repo.checkApplies(patch)
+}
+
+// TestQuotePath checks that paths are quoted as git quotes them and that the parser
+// reads the quoted paths back
+func TestQuotePath(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"a/plain.go", "a/plain.go"},
+		{"a/sp ace.go", "a/sp ace.go"},
+		{"a/ü.txt", `"a/\303\274.txt"`},
+		{"a/日本.go", `"a/\346\227\245\346\234\254.go"`},
+		{`a/say "hi".txt`, `"a/say \"hi\".txt"`},
+		{`a/back\slash`, `"a/back\\slash"`},
+		{"a/tab\there\nline", `"a/tab\there\nline"`},
+		{"a/bell\a\x1b\x7f", `"a/bell\a\033\177"`},
+	}
+	for _, tt := range tests {
+		got := QuotePath(tt.path)
+		if got != tt.want {
+			t.Errorf("QuotePath(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+		if got != tt.path {
+			if unquoted, rest, ok := cutQuoted(got); !ok || rest != "" || unquoted != tt.path {
+				t.Errorf("cutQuoted(%s) = %q, %q, %v, want %q", got, unquoted, rest, ok, tt.path)
+			}
+		}
+	}
+}
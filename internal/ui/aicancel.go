@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// aiRequestTimeout bounds how long a single AI request may run
+const aiRequestTimeout = 60 * time.Second
+
+// aiCanceledText is shown in an AI view after its request was canceled
+const aiCanceledText = "Canceled"
+
+// aiCanceledMsg is sent by an AI command whose request was canceled with esc or q
+type aiCanceledMsg struct{}
+
+// startAIRequest creates the context for a new AI request, canceling any request still
+// in flight. The cancel func is kept on the model so esc/q can abort the request.
+func (m *model) startAIRequest() (context.Context, context.CancelFunc) {
+	if m.aiCancel != nil {
+		m.aiCancel()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), aiRequestTimeout)
+	m.aiCancel = cancel
+	return ctx, cancel
+}
+
+// cancelAIRequest aborts the in-flight AI request and marks the view as canceled
+func (m *model) cancelAIRequest() {
+	if m.aiCancel != nil {
+		m.aiCancel()
+		m.aiCancel = nil
+	}
+	m.aiLoading = false
+	m.aiError = aiCanceledText
+}
+
+// aiCanceled reports whether an AI request failed because it was canceled by the user
+func aiCanceled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/ai"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// withheldFiles returns the files .criticaignore keeps out of the AI context
+func (m model) withheldFiles(files []parser.FileDiff) []string {
+	if m.aiService == nil {
+		return nil
+	}
+	return m.aiService.WithheldFiles(m.aiContextFiles(files))
+}
+
+// renderWithheldNote renders a note listing the files that were not sent to the AI, how
+// many secrets were redacted from the last prompt and whether it fell back from streaming
+func (m model) renderWithheldNote(withheld []string) string {
+	var notes []string
+	if len(withheld) > 0 {
+		noun := "files"
+		if len(withheld) == 1 {
+			noun = "file"
+		}
+		notes = append(notes, fmt.Sprintf("🔒 %d %s withheld from the AI by %s: %s",
+			len(withheld), noun, ai.IgnoreFileName, strings.Join(withheld, ", ")))
+	}
+	if m.aiService != nil {
+		if n := m.aiService.LastRedactions(); n > 0 {
+			noun := "secrets"
+			if n == 1 {
+				noun = "secret"
+			}
+			notes = append(notes, fmt.Sprintf("🔒 %d likely %s redacted before sending to the AI", n, noun))
+		}
+		if m.aiService.LastStreamFallback() {
+			notes = append(notes, "(streaming unavailable, fell back to a regular request)")
+		}
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+
+	noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+	return noteStyle.Render(wrapText(strings.Join(notes, "\n"), m.width)) + "\n"
+}
+
+// renderAIModel renders a subtitle naming the model and provider that answer AI requests
+func (m model) renderAIModel() string {
+	if m.aiService == nil {
+		return ""
+	}
+	modelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+	return modelStyle.Render(fmt.Sprintf("Model: %s · %s", m.aiService.ModelName(), m.aiService.ProviderName())) + "\n"
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"errors"
+
+	"github.com/danielss-dev/critica/internal/ai"
+)
+
+// aiErrorText returns the message shown for a failed AI request, followed by a hint on
+// how to get past the error when the AI layer identified it
+func aiErrorText(err error) string {
+	hint := ""
+	switch {
+	case errors.Is(err, ai.ErrContextLengthExceeded):
+		hint = "Reduce the diff sent to the model: press esc, then f to narrow to staged files, lower ai_max_files in the config, or list generated files in " + ai.IgnoreFileName + "."
+	case errors.Is(err, ai.ErrRateLimited):
+		hint = "The provider is throttling requests. Wait a moment, then press r to retry."
+	case errors.Is(err, ai.ErrNoAPIKey):
+		hint = "Check that OPENAI_API_KEY is set to a valid key for the configured provider."
+	case errors.Is(err, ai.ErrInvalidResponse):
+		hint = "The model's reply couldn't be read. Press r to retry, or try another model with OPENAI_MODEL."
+	}
+
+	if hint == "" {
+		return err.Error()
+	}
+	return err.Error() + "\n\n" + hint
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// aiScope selects which files analysis, improvements and explanations cover
+type aiScope int
+
+const (
+	// aiScopeAll covers every file in the current filter
+	aiScopeAll aiScope = iota
+	// aiScopeCurrent covers only the file open in the diff view
+	aiScopeCurrent
+)
+
+// aiScopeAvailable reports whether the AI menu was opened from a file's diff, the only
+// place a current file exists to narrow the scope to
+func (m model) aiScopeAvailable() bool {
+	return m.previousViewMode == diffView && m.selectedIdx >= 0 && m.selectedIdx < len(m.files)
+}
+
+// aiScopeFiles returns the files the scoped AI actions run on
+func (m model) aiScopeFiles() []parser.FileDiff {
+	if m.aiScope == aiScopeCurrent && m.aiScopeAvailable() {
+		return m.files[m.selectedIdx : m.selectedIdx+1]
+	}
+	return m.files
+}
+
+// toggleAIScope switches between the open file and all files, when a file is open
+func (m *model) toggleAIScope() {
+	if !m.aiScopeAvailable() {
+		return
+	}
+	if m.aiScope == aiScopeAll {
+		m.aiScope = aiScopeCurrent
+	} else {
+		m.aiScope = aiScopeAll
+	}
+	m.list.Title = m.aiMenuTitle()
+}
+
+// aiMenuTitle names the AI menu and the files its actions cover
+func (m model) aiMenuTitle() string {
+	if m.aiScope == aiScopeCurrent && m.aiScopeAvailable() {
+		return fmt.Sprintf("AI Functions (current file: %s)", m.displayPath(m.files[m.selectedIdx].NewPath, maxFileListPathLength))
+	}
+	noun := "files"
+	if len(m.files) == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("AI Functions (all %d %s)", len(m.files), noun)
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/config"
+)
+
+// renderAIUnavailable explains how to enable AI when no AI service is configured,
+// instead of showing a menu whose actions would all do nothing
+func (m model) renderAIUnavailable() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(1, 0)
+	textStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f0f6fc"))
+	codeStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#21262d")).
+		Padding(0, 1)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8b949e")).
+		Margin(1, 0)
+
+	b.WriteString(titleStyle.Render("🤖 AI features are not enabled"))
+	b.WriteString("\n\n")
+	b.WriteString(textStyle.Render(wrapText("No AI provider is configured, so analysis, commit messages, PR descriptions and reviews are unavailable. To enable them, set your API key and restart critica:", m.width)))
+	b.WriteString("\n\n")
+	b.WriteString(codeStyle.Render(`export OPENAI_API_KEY="your-api-key-here"`))
+	b.WriteString("\n\n")
+	b.WriteString(textStyle.Render(wrapText("Use OPENAI_MODEL and OPENAI_BASE_URL to pick a model or point at an OpenAI-compatible provider.", m.width)))
+	b.WriteString("\n")
+
+	if path, err := config.DefaultPath(); err == nil {
+		b.WriteString("\n")
+		b.WriteString(textStyle.Render(wrapText("Other AI settings live in "+path+" (see \"AI Configuration\" in the README).", m.width)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("esc: back | q: quit"))
+	return b.String()
+}
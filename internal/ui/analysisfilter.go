@@ -0,0 +1,52 @@
+package ui
+
+// analysisFilter selects which sections of the AI analysis are shown
+type analysisFilter int
+
+const (
+	analysisFilterAll analysisFilter = iota
+	analysisFilterIssues
+	analysisFilterImprovements
+)
+
+// analysisSection identifies a section of the AI analysis view
+type analysisSection int
+
+const (
+	sectionSummary analysisSection = iota
+	sectionCodeQuality
+	sectionIssues
+	sectionImprovements
+	sectionSecurity
+	sectionPerformance
+	sectionCommitMessage
+	sectionPRDescription
+)
+
+// next cycles all -> issues+security -> improvements -> all
+func (f analysisFilter) next() analysisFilter {
+	return (f + 1) % 3
+}
+
+func (f analysisFilter) label() string {
+	switch f {
+	case analysisFilterIssues:
+		return "Issues & Security"
+	case analysisFilterImprovements:
+		return "Improvements & Performance"
+	default:
+		return "All sections"
+	}
+}
+
+// shows reports whether a section is visible under the filter
+func (f analysisFilter) shows(section analysisSection) bool {
+	switch f {
+	case analysisFilterIssues:
+		return section == sectionIssues || section == sectionSecurity
+	case analysisFilterImprovements:
+		return section == sectionImprovements || section == sectionPerformance
+	default:
+		return true
+	}
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// analysisSectionEntry is a non-empty section of the analysis shown under the current filter
+type analysisSectionEntry struct {
+	section analysisSection
+	title   string // Section header
+	label   string // Short name for the table of contents
+	count   int    // Number of items, or 0 for free-text sections
+}
+
+// analysisSections returns the sections of the current analysis that have content and
+// pass the filter, in display order
+func (m model) analysisSections() []analysisSectionEntry {
+	r := m.aiResult
+	if r == nil {
+		return nil
+	}
+
+	candidates := []struct {
+		entry   analysisSectionEntry
+		present bool
+	}{
+		{analysisSectionEntry{sectionSummary, "📝 Summary:", "Summary", 0}, r.Summary != ""},
+		{analysisSectionEntry{sectionCodeQuality, "🏆 Code Quality:", "Quality", 0}, r.CodeQuality != ""},
+		{analysisSectionEntry{sectionIssues, "⚠️  Issues Found:", "Issues", len(r.Issues)}, len(r.Issues) > 0},
+		{analysisSectionEntry{sectionImprovements, "💡 Improvement Suggestions:", "Improvements", len(r.Improvements)}, len(r.Improvements) > 0},
+		{analysisSectionEntry{sectionSecurity, "🔒 Security Notes:", "Security", len(r.SecurityNotes)}, len(r.SecurityNotes) > 0},
+		{analysisSectionEntry{sectionPerformance, "⚡ Performance Notes:", "Performance", len(r.PerformanceNotes)}, len(r.PerformanceNotes) > 0},
+		{analysisSectionEntry{sectionCommitMessage, "📝 Suggested Commit Message:", "Commit", 0}, r.CommitMessage != ""},
+		{analysisSectionEntry{sectionPRDescription, "📋 PR Description:", "PR", 0}, r.PRDescription != ""},
+	}
+
+	var entries []analysisSectionEntry
+	for _, c := range candidates {
+		if c.present && m.analysisFilter.shows(c.entry.section) {
+			entries = append(entries, c.entry)
+		}
+	}
+	return entries
+}
+
+// renderAnalysisTOC renders the table of contents: one entry per section with its item
+// count, the selected section highlighted
+func (m model) renderAnalysisTOC(entries []analysisSectionEntry) string {
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#58a6ff"))
+
+	parts := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		text := entry.label
+		if entry.count > 0 {
+			text = fmt.Sprintf("%s (%d)", entry.label, entry.count)
+		}
+		if m.analysisFolded[entry.section] {
+			text += " ▸"
+		}
+		if i == m.analysisSectionIdx {
+			parts = append(parts, selectedStyle.Render("["+text+"]"))
+		} else {
+			parts = append(parts, itemStyle.Render(text))
+		}
+	}
+
+	return lipgloss.NewStyle().Width(m.width).Render(itemStyle.Render("Contents: ") + strings.Join(parts, itemStyle.Render(" · ")))
+}
+
+// renderAnalysisSection renders a section header and, unless folded, its body
+func (m model) renderAnalysisSection(entry analysisSectionEntry, selected bool) string {
+	var b strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#f0f6fc")).
+		Margin(0, 0, 1, 0)
+	if selected {
+		sectionStyle = sectionStyle.Foreground(lipgloss.Color("#58a6ff"))
+	}
+
+	folded := m.analysisFolded[entry.section]
+	marker := "▾ "
+	title := entry.title
+	if folded {
+		marker = "▸ "
+		if entry.count > 0 {
+			title = fmt.Sprintf("%s (%d hidden)", strings.TrimSuffix(title, ":"), entry.count)
+		} else {
+			title = strings.TrimSuffix(title, ":") + " (hidden)"
+		}
+	}
+	b.WriteString(sectionStyle.Render(marker + title))
+	b.WriteString("\n")
+	if folded {
+		return b.String()
+	}
+
+	r := m.aiResult
+	numbered := func(items []string) {
+		for i, item := range items {
+			b.WriteString(wrapText(fmt.Sprintf("  %d. %s", i+1, item), m.width))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+	codeStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("#21262d")).
+		Padding(1).
+		Margin(0, 0, 1, 0)
+
+	switch entry.section {
+	case sectionSummary:
+		b.WriteString(m.renderAIText(r.Summary, m.width))
+		b.WriteString("\n\n")
+	case sectionCodeQuality:
+		b.WriteString(wrapText(r.CodeQuality, m.width))
+		b.WriteString("\n\n")
+	case sectionIssues:
+		numbered(r.Issues)
+	case sectionImprovements:
+		numbered(r.Improvements)
+	case sectionSecurity:
+		numbered(r.SecurityNotes)
+	case sectionPerformance:
+		numbered(r.PerformanceNotes)
+	case sectionCommitMessage:
+		b.WriteString(codeStyle.Render(wrapText(r.CommitMessage, m.width-2)))
+		b.WriteString("\n")
+	case sectionPRDescription:
+		b.WriteString(codeStyle.Render(wrapText(r.PRDescription, m.width-2)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// analysisContent renders the analysis view before viewport scrolling, along with the line
+// each section header starts on. final is true for the loading and error screens, which
+// are shown as-is.
+func (m model) analysisContent() (string, []int, bool) {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(1, 0)
+
+	b.WriteString(titleStyle.Render("🤖 AI Analysis Results"))
+	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
+
+	if m.aiLoading {
+		loadingStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e")).
+			Margin(1, 0)
+		b.WriteString(loadingStyle.Render("Analyzing changes with AI... (esc: cancel)"))
+		return b.String(), nil, true
+	}
+
+	if m.aiError != "" {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#f85149")).
+			Margin(1, 0)
+		b.WriteString(errorStyle.Render("Error: " + m.aiError))
+		b.WriteString("\n\n")
+		helpStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e"))
+		b.WriteString(helpStyle.Render("Press 'r' to retry or 'esc' to go back"))
+		return b.String(), nil, true
+	}
+
+	if m.aiResult == nil {
+		return b.String(), nil, true
+	}
+
+	b.WriteString(m.renderWithheldNote(m.withheldFiles(m.aiScopeFiles())))
+
+	if m.analysisFilter != analysisFilterAll {
+		filterStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#d29922"))
+		b.WriteString(filterStyle.Render("Showing: " + m.analysisFilter.label()))
+		b.WriteString("\n\n")
+	}
+
+	entries := m.analysisSections()
+	if len(entries) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e"))
+		b.WriteString(emptyStyle.Render("Nothing to show in these sections. Press 's' to change the filter."))
+		b.WriteString("\n")
+		b.WriteString(m.renderCopyStatus())
+		return b.String(), nil, false
+	}
+
+	b.WriteString(m.renderAnalysisTOC(entries))
+	b.WriteString("\n\n")
+
+	headers := make([]int, len(entries))
+	for i, entry := range entries {
+		headers[i] = strings.Count(b.String(), "\n")
+		b.WriteString(m.renderAnalysisSection(entry, i == m.analysisSectionIdx))
+	}
+	b.WriteString(m.renderCopyStatus())
+
+	return b.String(), headers, false
+}
+
+// selectAnalysisSection selects the idx-th section (wrapping) in the table of contents
+// and scrolls its header to the top of the viewport
+func (m *model) selectAnalysisSection(idx int) {
+	entries := m.analysisSections()
+	if len(entries) == 0 {
+		return
+	}
+	m.analysisSectionIdx = (idx + len(entries)) % len(entries)
+
+	_, headers, _ := m.analysisContent()
+	if m.analysisSectionIdx < len(headers) {
+		m.scrollOffset = headers[m.analysisSectionIdx]
+	}
+}
+
+// toggleAnalysisSection folds or unfolds the selected section
+func (m *model) toggleAnalysisSection() {
+	entries := m.analysisSections()
+	if m.analysisSectionIdx >= len(entries) {
+		return
+	}
+	section := entries[m.analysisSectionIdx].section
+	if m.analysisFolded == nil {
+		m.analysisFolded = make(map[analysisSection]bool)
+	}
+	m.analysisFolded[section] = !m.analysisFolded[section]
+	m.selectAnalysisSection(m.analysisSectionIdx)
+}
+
+// resetAnalysisSections unfolds every section and selects the first, for a new analysis
+func (m *model) resetAnalysisSections() {
+	m.analysisFolded = make(map[analysisSection]bool)
+	m.analysisSectionIdx = 0
+}
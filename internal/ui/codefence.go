@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fenceLanguage reports whether line opens or closes a fenced code block and
+// returns the language named after the fence, if any
+func fenceLanguage(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(trimmed, fence) {
+			lang := strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1]))
+			if fields := strings.Fields(lang); len(fields) > 0 {
+				lang = fields[0]
+			}
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// fenceLexer returns the lexer for a fence language tag, or nil if it isn't recognized
+func (r *Renderer) fenceLexer(lang string) chroma.Lexer {
+	if !r.useColor || lang == "" {
+		return nil
+	}
+	return lexers.Get(lang)
+}
+
+// highlightFencedCode wraps prose to width and syntax-highlights the contents of
+// fenced code blocks using the lexer named by the fence. Code lines are not wrapped.
+func (r *Renderer) highlightFencedCode(text string, width int) string {
+	fenceStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+
+	var out []string
+	var prose, code []string
+	var lexer chroma.Lexer
+	inCode := false
+
+	flushProse := func() {
+		if len(prose) > 0 {
+			out = append(out, wrapText(strings.Join(prose, "\n"), width))
+			prose = nil
+		}
+	}
+	flushCode := func() {
+		if len(code) > 0 {
+			out = append(out, r.highlightCode(strings.Join(code, "\n"), lexer))
+			code = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		lang, isFence := fenceLanguage(line)
+		switch {
+		case isFence && !inCode:
+			flushProse()
+			lexer = r.fenceLexer(lang)
+			inCode = true
+			out = append(out, fenceStyle.Render(line))
+		case isFence && inCode && lang == "":
+			flushCode()
+			inCode = false
+			out = append(out, fenceStyle.Render(line))
+		case inCode:
+			code = append(code, line)
+		default:
+			prose = append(prose, line)
+		}
+	}
+
+	// An unterminated fence still highlights what was received
+	flushCode()
+	flushProse()
+
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// matchesCollapsePath reports whether a diff path matches any of the collapse globs.
+// Patterns ending in "/" match a directory anywhere in the path, patterns without a
+// "/" match the file name, and all other patterns match the full path.
+func matchesCollapsePath(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+
+		if strings.HasSuffix(pattern, "/**") {
+			pattern = strings.TrimSuffix(pattern, "**")
+		}
+
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(filePath, pattern) || strings.Contains(filePath, "/"+pattern) {
+				return true
+			}
+			continue
+		}
+
+		target := filePath
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(filePath)
+		}
+		if matched, err := path.Match(pattern, target); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sortCollapsedLast returns a copy of files with collapse-matching files moved to the
+// bottom, preserving the original order within each group.
+func sortCollapsedLast(files []parser.FileDiff, patterns []string) []parser.FileDiff {
+	if len(patterns) == 0 {
+		return files
+	}
+
+	sorted := make([]parser.FileDiff, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return !matchesCollapsePath(sorted[i].NewPath, patterns) && matchesCollapsePath(sorted[j].NewPath, patterns)
+	})
+	return sorted
+}
+
+// isAutoCollapsed reports whether a file matches collapse_paths and hasn't been expanded by the user
+func (m model) isAutoCollapsed(file parser.FileDiff) bool {
+	return matchesCollapsePath(file.NewPath, m.collapsePaths) && !m.expandedPaths[file.NewPath]
+}
+
+// aiContextFiles drops auto-collapsed files from the AI context unless the user opened them
+func (m model) aiContextFiles(files []parser.FileDiff) []parser.FileDiff {
+	if len(m.collapsePaths) == 0 {
+		return files
+	}
+
+	result := make([]parser.FileDiff, 0, len(files))
+	for _, file := range files {
+		if m.isAutoCollapsed(file) {
+			continue
+		}
+		result = append(result, file)
+	}
+	return result
+}
+
+// commitContextFiles returns the files a commit message is generated from: the AI context
+// files, or all of files when every one of them is auto-collapsed (such as a commit that
+// only updates go.sum), since the message has to describe something
+func (m model) commitContextFiles(files []parser.FileDiff) []parser.FileDiff {
+	if context := m.aiContextFiles(files); len(context) > 0 {
+		return context
+	}
+	return files
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// TestCommitContextFiles checks that auto-collapsed files stay out of the commit message
+// context unless nothing else is left to describe
+func TestCommitContextFiles(t *testing.T) {
+	m := model{collapsePaths: []string{"go.sum"}, expandedPaths: make(map[string]bool)}
+	sum := parser.FileDiff{OldPath: "go.sum", NewPath: "go.sum"}
+	code := parser.FileDiff{OldPath: "main.go", NewPath: "main.go"}
+
+	tests := []struct {
+		name  string
+		files []parser.FileDiff
+		want  []string
+	}{
+		{"mixed", []parser.FileDiff{sum, code}, []string{"main.go"}},
+		{"only collapsed", []parser.FileDiff{sum}, []string{"go.sum"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, file := range m.commitContextFiles(tt.files) {
+			got = append(got, file.NewPath)
+		}
+		if len(got) != len(tt.want) || got[0] != tt.want[0] {
+			t.Errorf("%s: commit context = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// commitCandidateCount is how many alternative commit messages are generated at once
+const commitCandidateCount = 3
+
+// selectCommitCandidate makes the idx-th generated candidate the commit message to apply or edit
+func (m *model) selectCommitCandidate(idx int) {
+	if idx < 0 || idx >= len(m.aiCommitCandidates) {
+		return
+	}
+	m.commitCandidateIdx = idx
+	m.aiCommitMsg = m.aiCommitCandidates[idx]
+	m.scrollOffset = 0
+}
+
+// canPickCommitCandidate reports whether the commit view is still choosing between candidates
+func (m model) canPickCommitCandidate() bool {
+	return m.viewMode == aiCommitView && !m.aiLoading && !m.commitApplied && len(m.aiCommitCandidates) > 1
+}
+
+// renderCommitCandidates lists the subject line of each candidate, marking the picked one
+func (m model) renderCommitCandidates() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f0f6fc")).
+		Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#58a6ff")).
+		Bold(true)
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📝 Candidates (%d)", len(m.aiCommitCandidates))))
+	b.WriteString(" ")
+	b.WriteString(hintStyle.Render(fmt.Sprintf("1-%d or h/l: pick", len(m.aiCommitCandidates))))
+	b.WriteString("\n")
+
+	subjectWidth := m.width - 8
+	for i, candidate := range m.aiCommitCandidates {
+		subject, _, _ := strings.Cut(candidate, "\n")
+		if subjectWidth > 0 {
+			if truncated, cut := truncateVisibleANSI(subject, subjectWidth-1); cut {
+				subject = truncated + "…"
+			}
+		}
+		line := fmt.Sprintf("%d. %s", i+1, subject)
+		if i == m.commitCandidateIdx {
+			b.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
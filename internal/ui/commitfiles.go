@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// commitScopeFiles returns the files a commit in the current scope will include
+func (m model) commitScopeFiles() []parser.FileDiff {
+	if m.commitScope == "staged" {
+		return m.stagedFiles
+	}
+	return m.allFiles
+}
+
+// fileLineCounts returns the number of added and deleted lines in a file diff
+func fileLineCounts(file parser.FileDiff) (int, int) {
+	added, deleted := 0, 0
+	for _, hunk := range file.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Type {
+			case parser.LineAdded:
+				added++
+			case parser.LineDeleted:
+				deleted++
+			}
+		}
+	}
+	return added, deleted
+}
+
+// fileStatusLetter returns a git-style status letter for a file diff
+func fileStatusLetter(file parser.FileDiff) string {
+	switch {
+	case file.IsNew:
+		return "A"
+	case file.IsDeleted:
+		return "D"
+	case file.IsRenamed:
+		return "R"
+	default:
+		return "M"
+	}
+}
+
+// renderCommitFiles renders the collapsible list of files in the commit scope with their
+// +/- counts, so unexpected files can be spotted before the commit is applied
+func (m model) renderCommitFiles(files []parser.FileDiff) string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f0f6fc")).
+		Bold(true)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+	addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#3fb950"))
+	deletedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#f85149"))
+
+	toggle := "f: hide"
+	if m.commitFilesCollapsed {
+		toggle = "f: show"
+	}
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📦 Files to commit (%d)", len(files))))
+	b.WriteString(" ")
+	b.WriteString(hintStyle.Render(toggle))
+	b.WriteString("\n")
+
+	if m.commitFilesCollapsed {
+		return b.String()
+	}
+
+	pathWidth := m.width - 20
+	if pathWidth < 20 {
+		pathWidth = maxFileListPathLength
+	}
+	for _, file := range files {
+		added, deleted := fileLineCounts(file)
+		b.WriteString(fmt.Sprintf("  %s %s  %s %s\n",
+			fileStatusLetter(file),
+			shortenPath(file.NewPath, pathWidth),
+			addedStyle.Render(fmt.Sprintf("+%d", added)),
+			deletedStyle.Render(fmt.Sprintf("-%d", deleted))))
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/danielss-dev/critica/internal/git"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// committedDiffMsg carries the diff of the commit that was just created
+type committedDiffMsg struct {
+	hash  string
+	files []parser.FileDiff
+	err   error
+}
+
+// canShowCommittedDiff reports whether the commit view offers to show what was committed;
+// a dry run creates no commit
+func (m model) canShowCommittedDiff() bool {
+	return m.viewMode == aiCommitView && m.commitApplied && !m.dryRun && m.loadDiff != nil
+}
+
+// showCommittedDiff loads the diff of HEAD, the commit just created, with git show
+func (m *model) showCommittedDiff() tea.Cmd {
+	m.committedDiffLoading = true
+	m.committedDiffError = ""
+	return func() tea.Msg {
+		hash, err := git.GetHeadHash(".")
+		if err != nil {
+			return committedDiffMsg{err: err}
+		}
+		diff, err := git.GetCommitDiff(".", hash)
+		if err != nil {
+			return committedDiffMsg{err: err}
+		}
+		files, err := parser.ParseDiff(diff)
+		return committedDiffMsg{hash: hash, files: files, err: err}
+	}
+}
+
+// applyCommittedDiff opens the committed files in the file list. They replace the loaded
+// changes, which the commit made stale; S reloads the working tree.
+func (m *model) applyCommittedDiff(msg committedDiffMsg) {
+	m.committedDiffLoading = false
+	if msg.err != nil {
+		m.committedDiffError = msg.err.Error()
+		return
+	}
+
+	m.shownCommit = msg.hash
+	m.allFiles = msg.files
+	m.stagedFiles = nil
+	m.unstagedFiles = nil
+	m.untrackedFiles = nil
+	m.fullFileHunks = make(map[fullFileKey][]parser.Hunk)
+	m.fullFileView = false
+	m.applyFilter(filterAll)
+	m.viewMode = fileListView
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/ai"
+)
+
+const scopePlaceholder = "scope (optional)"
+
+// commitTypeChoices are the options in the commit type picker; "auto" lets the model choose
+var commitTypeChoices = append([]string{"auto"}, ai.CommitTypes...)
+
+// startCommitTypePicker shows the type/scope picker that precedes commit message generation
+func (m *model) startCommitTypePicker() {
+	m.viewMode = aiCommitTypeView
+	m.commitTypeIdx = 0
+	m.commitScopeEditing = false
+	m.commitOptions = ai.CommitOptions{}
+	m.aiLoading = false
+	m.aiError = ""
+	m.scrollOffset = 0
+}
+
+// startCommitGeneration switches to the commit view and generates a message with the picked options
+func (m *model) startCommitGeneration() tea.Cmd {
+	m.viewMode = aiCommitView
+	m.aiLoading = true
+	m.aiError = ""
+	m.scrollOffset = 0
+	return m.generateCommitMessage()
+}
+
+// stopScopeInput returns the shared text input to its search role
+func (m *model) stopScopeInput() {
+	m.commitScopeEditing = false
+	m.textInput.Blur()
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = searchPlaceholder
+}
+
+func (m model) updateCommitType(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.commitScopeEditing {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "enter":
+			m.commitOptions.Scope = strings.TrimSpace(m.textInput.Value())
+			m.stopScopeInput()
+			return m, m.startCommitGeneration()
+
+		case "esc":
+			// Back to the type list
+			m.stopScopeInput()
+			m.commitOptions = ai.CommitOptions{}
+			return m, nil
+
+		default:
+			var cmd tea.Cmd
+			m.textInput, cmd = m.textInput.Update(msg)
+			return m, cmd
+		}
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		m.viewMode = aiMenuView
+		return m, nil
+
+	case "j", "down":
+		if m.commitTypeIdx < len(commitTypeChoices)-1 {
+			m.commitTypeIdx++
+		}
+		return m, nil
+
+	case "k", "up":
+		if m.commitTypeIdx > 0 {
+			m.commitTypeIdx--
+		}
+		return m, nil
+
+	case "s":
+		// Skip the picker and let the model choose
+		m.commitOptions = ai.CommitOptions{}
+		return m, m.startCommitGeneration()
+
+	case "enter":
+		if m.commitTypeIdx == 0 {
+			m.commitOptions = ai.CommitOptions{}
+			return m, m.startCommitGeneration()
+		}
+		m.commitOptions = ai.CommitOptions{Type: commitTypeChoices[m.commitTypeIdx]}
+		m.commitScopeEditing = true
+		m.textInput.Placeholder = scopePlaceholder
+		m.textInput.SetValue("")
+		m.textInput.Focus()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m model) renderAICommitType() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(1, 0)
+	questionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f0f6fc")).
+		Bold(true)
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#58a6ff")).
+		Bold(true)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8b949e")).
+		Margin(1, 0)
+
+	b.WriteString(titleStyle.Render(m.dryRunTitle("🤖 AI Commit Message")))
+	b.WriteString("\n\n")
+
+	if m.commitScopeEditing {
+		b.WriteString(questionStyle.Render("Scope for " + m.commitOptions.Type + " (leave empty for none):"))
+		b.WriteString("\n\n")
+		b.WriteString(m.textInput.View())
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("enter: generate | esc: back to types"))
+		return b.String()
+	}
+
+	b.WriteString(questionStyle.Render("Commit type:"))
+	b.WriteString("\n\n")
+	for i, choice := range commitTypeChoices {
+		label := choice
+		if i == 0 {
+			label = "auto (let the AI choose)"
+		}
+		if i == m.commitTypeIdx {
+			b.WriteString(selectedStyle.Render("> " + label))
+		} else {
+			b.WriteString("  " + label)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("j/k: move | enter: select | s: skip (auto) | esc: back"))
+	return b.String()
+}
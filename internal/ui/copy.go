@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// copyText returns the plain-text content of the current AI view for the clipboard,
+// or "" if the view has nothing to copy
+func (m model) copyText() string {
+	if m.showingRawResponse() {
+		return m.aiRawResponse
+	}
+
+	switch m.viewMode {
+	case aiAnalysisView:
+		return m.analysisText()
+	case aiImproveView:
+		if m.improveSelectedIdx < len(m.aiImprovements) {
+			return m.aiImprovements[m.improveSelectedIdx]
+		}
+		return numberedList(m.aiImprovements)
+	case aiExplainView:
+		return m.aiExplanation
+	case aiPRView:
+		return m.aiPRDesc
+	case aiFullReviewView:
+		return m.fullReviewText()
+	}
+	return ""
+}
+
+// analysisText formats the analysis sections visible under the current filter as Markdown
+func (m model) analysisText() string {
+	if m.aiResult == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	writeText := func(section analysisSection, title, text string) {
+		if text == "" || !m.analysisFilter.shows(section) {
+			return
+		}
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", title, text)
+	}
+	writeList := func(section analysisSection, title string, items []string) {
+		if len(items) == 0 || !m.analysisFilter.shows(section) {
+			return
+		}
+		fmt.Fprintf(&b, "## %s\n\n%s\n", title, numberedList(items))
+	}
+
+	writeText(sectionSummary, "Summary", m.aiResult.Summary)
+	writeText(sectionCodeQuality, "Code Quality", m.aiResult.CodeQuality)
+	writeList(sectionIssues, "Issues Found", m.aiResult.Issues)
+	writeList(sectionImprovements, "Improvement Suggestions", m.aiResult.Improvements)
+	writeList(sectionSecurity, "Security Notes", m.aiResult.SecurityNotes)
+	writeList(sectionPerformance, "Performance Notes", m.aiResult.PerformanceNotes)
+	writeText(sectionCommitMessage, "Suggested Commit Message", m.aiResult.CommitMessage)
+	writeText(sectionPRDescription, "PR Description", m.aiResult.PRDescription)
+
+	return strings.TrimSpace(b.String())
+}
+
+// numberedList formats items as a "1. item" list, one per line
+func numberedList(items []string) string {
+	var b strings.Builder
+	for i, item := range items {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, item)
+	}
+	return b.String()
+}
+
+// renderCopyStatus renders the confirmation shown after content was copied
+func (m model) renderCopyStatus() string {
+	if !m.copySuccess {
+		return ""
+	}
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#3fb950")).
+		Bold(true)
+	return successStyle.Render("✅ Copied to clipboard!") + "\n\n"
+}
@@ -0,0 +1,89 @@
+package ui
+
+import "github.com/danielss-dev/critica/internal/parser"
+
+// diffLinesKey is everything a file's rendered diff lines depend on. Scrolling changes
+// none of it, so it only reslices the cached lines.
+type diffLinesKey struct {
+	path      string
+	hunks     *parser.Hunk // First hunk; reloading or the full-file view replaces the slice
+	hunkCount int
+	width     int
+	unified   bool
+	theme     *Theme // Replaced when the diff style is switched
+	fullFile  bool
+	expanded  bool // Review comments shown inline
+}
+
+// diffLinesCache keeps the rendered lines of the file in the diff view, since View runs on
+// every keypress and highlighting every hunk of a large file again is slow. It is shared
+// by the copies of the model, like markdownCache.
+type diffLinesCache struct {
+	key        diffLinesKey
+	valid      bool
+	lines      []string
+	hunkStarts []int
+}
+
+// reset drops the cached lines; needed when something outside the key changes, such as
+// the review comments
+func (c *diffLinesCache) reset() {
+	c.valid = false
+	c.lines = nil
+	c.hunkStarts = nil
+}
+
+// diffLinesKeyFor returns the cache key for rendering file in the current view
+func (m model) diffLinesKeyFor(file parser.FileDiff) diffLinesKey {
+	key := diffLinesKey{
+		path:      file.NewPath,
+		hunkCount: len(file.Hunks),
+		width:     m.width,
+		unified:   m.renderer.unified,
+		theme:     m.renderer.theme,
+		fullFile:  m.fullFileView,
+		expanded:  m.reviewExpanded,
+	}
+	if len(file.Hunks) > 0 {
+		key.hunks = &file.Hunks[0]
+	}
+	return key
+}
+
+// movedLinesKey identifies a file passed through parser.MarkMovedLines
+type movedLinesKey struct {
+	path      string
+	hunks     *parser.Hunk // First hunk; reloading or the full-file view replaces the slice
+	hunkCount int
+	minLines  int
+}
+
+// movedLinesCache keeps the moved-line marks of the last file they were computed for. The
+// preview renders on every keypress, and matching moved blocks is slow for large files
+// with many repeated lines. It is shared by the copies of the model, like diffLinesCache.
+type movedLinesCache struct {
+	key   movedLinesKey
+	valid bool
+	file  parser.FileDiff
+}
+
+// markMovedLines returns file with its moved lines marked, reusing the result for the
+// file it was last computed for
+func (m model) markMovedLines(file parser.FileDiff) parser.FileDiff {
+	if m.movedLines == nil || len(file.Hunks) == 0 {
+		return parser.MarkMovedLines(file, m.renderer.movedMinLines)
+	}
+
+	key := movedLinesKey{
+		path:      file.NewPath,
+		hunks:     &file.Hunks[0],
+		hunkCount: len(file.Hunks),
+		minLines:  m.renderer.movedMinLines,
+	}
+	if !m.movedLines.valid || m.movedLines.key != key {
+		m.movedLines.key = key
+		m.movedLines.file = parser.MarkMovedLines(file, m.renderer.movedMinLines)
+		m.movedLines.valid = true
+	}
+	return m.movedLines.file
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/danielss-dev/critica/internal/git"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// DiffSet is the changes an interactive session shows: the main diff plus the staged,
+// unstaged and untracked files the file filter switches between
+type DiffSet struct {
+	All       []parser.FileDiff
+	Staged    []parser.FileDiff
+	Unstaged  []parser.FileDiff
+	Untracked []parser.FileDiff
+}
+
+// diffReloadedMsg carries the changes loaded after the diff source was switched
+type diffReloadedMsg struct {
+	seq  int // Matches diffReloadSeq unless a newer switch superseded this one
+	mode git.DiffMode
+	set  DiffSet
+	err  error
+}
+
+// diffSourceName names a diff source for the file list title
+func diffSourceName(mode git.DiffMode) string {
+	switch mode {
+	case git.DiffModeStaged:
+		return "staged"
+	case git.DiffModeUnstaged:
+		return "unstaged"
+	default:
+		return "all changes"
+	}
+}
+
+// nextDiffSource cycles all changes → staged → unstaged
+func nextDiffSource(mode git.DiffMode) git.DiffMode {
+	switch mode {
+	case git.DiffModeAll:
+		return git.DiffModeStaged
+	case git.DiffModeStaged:
+		return git.DiffModeUnstaged
+	default:
+		return git.DiffModeAll
+	}
+}
+
+// switchDiffSource re-runs git diff for the next diff source and reloads every file list.
+// Switching again while a reload is running supersedes it.
+func (m *model) switchDiffSource() tea.Cmd {
+	if m.loadDiff == nil {
+		return nil
+	}
+	mode := nextDiffSource(m.pendingDiffSource())
+	if m.shownCommit != "" && !m.diffReloading {
+		// Go back to the changes the commit was made from
+		mode = m.diffSource
+	}
+	m.diffReloadSeq++
+	m.diffReloading = true
+	m.diffReloadTarget = mode
+	m.diffReloadError = ""
+	m.updateListTitle()
+
+	seq, load := m.diffReloadSeq, m.loadDiff
+	return func() tea.Msg {
+		set, err := load(mode)
+		return diffReloadedMsg{seq: seq, mode: mode, set: set, err: err}
+	}
+}
+
+// pendingDiffSource returns the source being loaded, or the current one
+func (m model) pendingDiffSource() git.DiffMode {
+	if m.diffReloading {
+		return m.diffReloadTarget
+	}
+	return m.diffSource
+}
+
+// applyDiffReload replaces the loaded changes, keeping the filter and, where the file is
+// still changed, the selected file
+func (m *model) applyDiffReload(msg diffReloadedMsg) {
+	if msg.seq != m.diffReloadSeq {
+		return
+	}
+	m.diffReloading = false
+	if msg.err != nil {
+		m.diffReloadError = msg.err.Error()
+		m.updateListTitle()
+		return
+	}
+
+	m.diffSource = msg.mode
+	m.shownCommit = ""
+	m.allFiles = msg.set.All
+	m.stagedFiles = msg.set.Staged
+	m.unstagedFiles = msg.set.Unstaged
+	m.untrackedFiles = msg.set.Untracked
+	// Cached full-file hunks were computed against the previous source
+	m.fullFileHunks = make(map[fullFileKey][]parser.Hunk)
+	m.fullFileView = false
+	m.applyFilter(m.filterMode)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dryRunRecorder collects the git commands skipped in dry-run mode. It is written from
+// command goroutines and read while rendering, so access is synchronized.
+type dryRunRecorder struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *dryRunRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		r.lines = append(r.lines, line)
+	}
+	return len(p), nil
+}
+
+// Lines returns the recorded commands
+func (r *dryRunRecorder) Lines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.lines...)
+}
+
+// dryRunTitle appends a dry-run marker to a view title when dry-run mode is on
+func (m model) dryRunTitle(title string) string {
+	if m.dryRun {
+		return title + " (dry run)"
+	}
+	return title
+}
+
+// renderDryRunLog renders the commands that dry-run mode skipped
+func (m model) renderDryRunLog() string {
+	if m.dryRunLog == nil {
+		return ""
+	}
+	lines := m.dryRunLog.Lines()
+	if len(lines) == 0 {
+		return ""
+	}
+
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#d29922"))
+	var b strings.Builder
+	b.WriteString(style.Render("Nothing was changed. Commands that would have run:"))
+	b.WriteString("\n")
+	for _, line := range lines {
+		b.WriteString("  " + strings.TrimPrefix(line, "[dry run] "))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
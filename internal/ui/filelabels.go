@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fileLabelWidth is the width labels are padded to, so the paths after them line up
+const fileLabelWidth = 4
+
+// fileLabel is the short language tag shown before a path in the file list
+type fileLabel struct {
+	text  string
+	color lipgloss.Color
+}
+
+// fileLabelsByExt maps lowercase extensions to their labels
+var fileLabelsByExt = map[string]fileLabel{
+	".go":    {"Go", "#00add8"},
+	".ts":    {"TS", "#3178c6"},
+	".tsx":   {"TSX", "#3178c6"},
+	".js":    {"JS", "#f1e05a"},
+	".jsx":   {"JSX", "#f1e05a"},
+	".mjs":   {"JS", "#f1e05a"},
+	".py":    {"Py", "#3572a5"},
+	".rs":    {"Rs", "#dea584"},
+	".rb":    {"Rb", "#cc342d"},
+	".java":  {"Java", "#b07219"},
+	".kt":    {"Kt", "#a97bff"},
+	".swift": {"Swft", "#f05138"},
+	".c":     {"C", "#8f9aa3"},
+	".h":     {"H", "#8f9aa3"},
+	".cpp":   {"C++", "#f34b7d"},
+	".cc":    {"C++", "#f34b7d"},
+	".hpp":   {"H++", "#f34b7d"},
+	".cs":    {"C#", "#178600"},
+	".php":   {"PHP", "#4f5d95"},
+	".sh":    {"Sh", "#89e051"},
+	".bash":  {"Sh", "#89e051"},
+	".zsh":   {"Sh", "#89e051"},
+	".html":  {"HTML", "#e34c26"},
+	".css":   {"CSS", "#663399"},
+	".scss":  {"SCSS", "#c6538c"},
+	".sql":   {"SQL", "#e38c00"},
+	".md":    {"MD", "#8b949e"},
+	".json":  {"JSON", "#cbcb41"},
+	".yaml":  {"YAML", "#cb171e"},
+	".yml":   {"YAML", "#cb171e"},
+	".toml":  {"TOML", "#9c4221"},
+	".xml":   {"XML", "#0060ac"},
+	".proto": {"PB", "#8b949e"},
+	".mod":   {"Mod", "#00add8"},
+	".sum":   {"Sum", "#00add8"},
+}
+
+// fileLabelsByName maps file names without a telling extension to their labels
+var fileLabelsByName = map[string]fileLabel{
+	"dockerfile": {"Dckr", "#384d54"},
+	"makefile":   {"Make", "#427819"},
+}
+
+// fileLabelFor returns the colored label for path padded to fileLabelWidth, or blank
+// padding when the file type is unknown
+func fileLabelFor(path string) string {
+	name := strings.ToLower(filepath.Base(path))
+	label, ok := fileLabelsByName[name]
+	if !ok {
+		label, ok = fileLabelsByExt[filepath.Ext(name)]
+	}
+	if !ok {
+		return strings.Repeat(" ", fileLabelWidth)
+	}
+
+	padding := strings.Repeat(" ", fileLabelWidth-len(label.text))
+	return lipgloss.NewStyle().Foreground(label.color).Bold(true).Render(label.text) + padding
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/danielss-dev/critica/internal/git"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// fullFileKey identifies a full-file diff: the same file diffs differently against the
+// index, HEAD or both
+type fullFileKey struct {
+	path string
+	mode git.DiffMode
+}
+
+type fullFileResultMsg struct {
+	key   fullFileKey
+	hunks []parser.Hunk
+}
+
+type fullFileErrorMsg struct {
+	err string
+}
+
+// diffModeForFilter maps the file list filter to the git comparison used for full-file diffs
+func diffModeForFilter(filter fileFilter) git.DiffMode {
+	switch filter {
+	case filterStaged:
+		return git.DiffModeStaged
+	case filterUnstaged:
+		return git.DiffModeUnstaged
+	case filterUntracked:
+		return git.DiffModeUntracked
+	default:
+		return git.DiffModeAll
+	}
+}
+
+// toggleFullFile switches the diff view between changed hunks and the whole file,
+// fetching the full-context diff the first time a file is expanded
+func (m *model) toggleFullFile() tea.Cmd {
+	m.scrollOffset = 0
+	m.fullFileError = ""
+	if m.fullFileView {
+		m.fullFileView = false
+		return nil
+	}
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.files) {
+		return nil
+	}
+	if m.shownCommit != "" {
+		// The file's sides are in the commit, not the index or working tree
+		m.fullFileError = "not available for a commit"
+		return nil
+	}
+
+	m.fullFileView = true
+	file := m.files[m.selectedIdx]
+	key := m.fullFileKey(file)
+	if _, ok := m.fullFileHunks[key]; ok {
+		return nil
+	}
+
+	m.fullFileLoading = true
+	mode := key.mode
+	return func() tea.Msg {
+		diff, err := git.GetFullFileDiff(m.repoPath, file.OldPath, file.NewPath, mode, m.sinceRef, file.IsNew, file.IsDeleted)
+		if err != nil {
+			return fullFileErrorMsg{err: err.Error()}
+		}
+		hunks, err := parser.ParseFileHunks(diff)
+		if err != nil {
+			return fullFileErrorMsg{err: err.Error()}
+		}
+		return fullFileResultMsg{key: key, hunks: hunks}
+	}
+}
+
+// fullFileKey returns the cache key of file's full-file diff under the current filter
+func (m model) fullFileKey(file parser.FileDiff) fullFileKey {
+	mode := diffModeForFilter(m.filterMode)
+	if m.filterMode == filterAll {
+		mode = m.diffSource
+	}
+	return fullFileKey{path: file.NewPath, mode: mode}
+}
+
+// displayedFile returns the file as shown in the diff view, with its hunks replaced by
+// the full-file diff when that view is active and loaded
+func (m model) displayedFile(file parser.FileDiff) parser.FileDiff {
+	if !m.fullFileView {
+		return file
+	}
+	if hunks, ok := m.fullFileHunks[m.fullFileKey(file)]; ok && len(hunks) > 0 {
+		file.Hunks = hunks
+	}
+	return file
+}
@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/ai"
+)
+
+// fullReviewStep is one of the requests a full review runs, in order
+type fullReviewStep int
+
+const (
+	fullReviewAnalysis fullReviewStep = iota
+	fullReviewImprovements
+	fullReviewExplanation
+	fullReviewStepCount
+)
+
+// label describes the step while it runs
+func (s fullReviewStep) label() string {
+	switch s {
+	case fullReviewAnalysis:
+		return "Analyzing changes"
+	case fullReviewImprovements:
+		return "Suggesting improvements"
+	default:
+		return "Explaining changes"
+	}
+}
+
+// name is the step's section of the report
+func (s fullReviewStep) name() string {
+	switch s {
+	case fullReviewAnalysis:
+		return "analysis"
+	case fullReviewImprovements:
+		return "improvements"
+	default:
+		return "explanation"
+	}
+}
+
+// aiFullReviewStepMsg carries the outcome of one full review step
+type aiFullReviewStepMsg struct {
+	step         fullReviewStep
+	result       *ai.AnalysisResult
+	improvements []string
+	explanation  string
+	raw          string // Response as the model returned it
+	err          string
+}
+
+// startFullReview clears the previous results and runs the first full review step
+func (m *model) startFullReview() tea.Cmd {
+	m.viewMode = aiFullReviewView
+	m.aiLoading = true
+	m.aiError = ""
+	m.scrollOffset = 0
+	m.aiResult = nil
+	m.aiImprovements = nil
+	m.aiExplanation = ""
+	m.aiRawResponse = ""
+	m.fullReviewErrors = make(map[fullReviewStep]string)
+	return m.runFullReviewStep(fullReviewAnalysis)
+}
+
+// runFullReviewStep runs one step of a full review. Steps run one after another rather
+// than in a batch so a single request is in flight and esc cancels the rest.
+func (m *model) runFullReviewStep(step fullReviewStep) tea.Cmd {
+	m.fullReviewStep = step
+	ctx, cancel := m.startAIRequest()
+	files := m.aiContextFiles(m.aiScopeFiles())
+	return func() tea.Msg {
+		defer cancel()
+
+		msg := aiFullReviewStepMsg{step: step}
+		var err error
+		switch step {
+		case fullReviewAnalysis:
+			msg.result, err = m.aiService.AnalyzeDiff(ctx, files)
+		case fullReviewImprovements:
+			msg.improvements, err = m.aiService.SuggestImprovements(ctx, files)
+		case fullReviewExplanation:
+			msg.explanation, err = m.aiService.ExplainChanges(ctx, files)
+		}
+		if err != nil {
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			msg.err = aiErrorText(err)
+		} else {
+			msg.raw = m.aiService.LastRawResponse()
+		}
+		return msg
+	}
+}
+
+// handleFullReviewStep stores a step's results and starts the next step. A failed step
+// is reported in its section of the report and doesn't stop the others.
+func (m *model) handleFullReviewStep(msg aiFullReviewStepMsg) tea.Cmd {
+	if !m.aiLoading {
+		// The review was canceled while this step was finishing
+		return nil
+	}
+	if msg.err != "" {
+		m.fullReviewErrors[msg.step] = msg.err
+	}
+	if msg.raw != "" {
+		// Keep every step's response, each under a header naming its step
+		m.aiRawResponse += fmt.Sprintf("===== %s =====\n%s\n\n", msg.step.name(), msg.raw)
+	}
+	switch msg.step {
+	case fullReviewAnalysis:
+		m.aiResult = msg.result
+		m.resetAnalysisSections()
+	case fullReviewImprovements:
+		m.aiImprovements = msg.improvements
+		m.improveSelectedIdx = 0
+	case fullReviewExplanation:
+		m.aiExplanation = msg.explanation
+	}
+
+	if next := msg.step + 1; next < fullReviewStepCount {
+		return m.runFullReviewStep(next)
+	}
+	m.aiLoading = false
+	return nil
+}
+
+// fullReviewText formats the whole report as Markdown for the clipboard
+func (m model) fullReviewText() string {
+	var b strings.Builder
+	if text := m.analysisText(); text != "" {
+		fmt.Fprintf(&b, "# Analysis\n\n%s\n\n", text)
+	}
+	if len(m.aiImprovements) > 0 {
+		fmt.Fprintf(&b, "# Improvements\n\n%s\n", numberedList(m.aiImprovements))
+	}
+	if m.aiExplanation != "" {
+		fmt.Fprintf(&b, "# Explanation\n\n%s\n", m.aiExplanation)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// fullReviewContent renders the report before viewport scrolling: the analysis sections,
+// then the improvements, then the explanation
+func (m model) fullReviewContent() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(1, 0)
+	headingStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(0, 0, 1, 0)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f85149")).
+		Margin(0, 0, 1, 0)
+
+	b.WriteString(titleStyle.Render("🤖 AI Full Review"))
+	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
+
+	if m.aiLoading {
+		loadingStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e")).
+			Margin(1, 0)
+		b.WriteString(loadingStyle.Render(fmt.Sprintf("%s (step %d of %d)... (esc: cancel)",
+			m.fullReviewStep.label(), m.fullReviewStep+1, fullReviewStepCount)))
+		return b.String()
+	}
+
+	if m.aiError != "" {
+		b.WriteString(errorStyle.Margin(1, 0).Render(fmt.Sprintf("Error: %s during step %d of %d. Press 'r' to rerun the review or 'esc' to go back",
+			m.aiError, m.fullReviewStep+1, fullReviewStepCount)))
+		b.WriteString("\n")
+	}
+	b.WriteString(m.renderWithheldNote(m.withheldFiles(m.aiScopeFiles())))
+
+	section := func(step fullReviewStep, title string, render func() string) {
+		if m.aiError != "" && step >= m.fullReviewStep {
+			// Canceled before this step finished
+			return
+		}
+		b.WriteString(headingStyle.Render(title))
+		b.WriteString("\n")
+		if err := m.fullReviewErrors[step]; err != "" {
+			b.WriteString(errorStyle.Render("Error: " + err))
+			b.WriteString("\n")
+			return
+		}
+		b.WriteString(render())
+	}
+
+	section(fullReviewAnalysis, "━━ Analysis", func() string {
+		var s strings.Builder
+		for _, entry := range m.analysisSections() {
+			s.WriteString(m.renderAnalysisSection(entry, false))
+		}
+		return s.String()
+	})
+	section(fullReviewImprovements, "━━ Improvements", func() string {
+		if len(m.aiImprovements) == 0 {
+			return "No improvement suggestions generated\n\n"
+		}
+		var s strings.Builder
+		for i, improvement := range m.aiImprovements {
+			s.WriteString(wrapText(fmt.Sprintf("  %d. %s", i+1, improvement), m.width))
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+		return s.String()
+	})
+	section(fullReviewExplanation, "━━ Explanation", func() string {
+		if m.aiExplanation == "" {
+			return "No explanation generated\n"
+		}
+		return m.renderAIText(m.aiExplanation, m.width) + "\n"
+	})
+	b.WriteString(m.renderCopyStatus())
+
+	return b.String()
+}
+
+func (m model) renderAIFullReview() string {
+	allLines := strings.Split(m.fullReviewContent(), "\n")
+	totalLines := len(allLines)
+	viewportHeight := m.aiViewportHeight()
+
+	// Clamp scroll offset
+	maxScroll := totalLines - viewportHeight
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	scrollOffset := m.scrollOffset
+	if scrollOffset > maxScroll {
+		scrollOffset = maxScroll
+	}
+	if scrollOffset < 0 {
+		scrollOffset = 0
+	}
+
+	endLine := scrollOffset + viewportHeight
+	if endLine > totalLines {
+		endLine = totalLines
+	}
+	result := strings.Join(allLines[scrollOffset:endLine], "\n")
+
+	if totalLines > viewportHeight {
+		result += "\n"
+		scrollInfo := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		percentage := int(float64(scrollOffset) / float64(maxScroll) * 100)
+		if scrollOffset >= maxScroll {
+			percentage = 100
+		}
+		result += scrollInfo.Render(fmt.Sprintf("[%d%%] Line %d-%d of %d", percentage, scrollOffset+1, endLine, totalLines))
+	}
+
+	result += "\n\n"
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8b949e")).
+		Margin(1, 0)
+	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: rerun | y: copy | m: " + m.rawToggleLabel() + " | v: raw response")
+
+	return result
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"github.com/alecthomas/chroma/v2"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// largeFileNote is added to the header of files rendered without syntax highlighting
+const largeFileNote = "syntax highlighting disabled (large file)"
+
+// skipsHighlighting reports whether file has more hunk lines than highlightLimit, in
+// which case it is rendered without syntax highlighting to keep rendering fast
+func (r *Renderer) skipsHighlighting(file parser.FileDiff) bool {
+	if !r.useColor || r.highlightLimit <= 0 {
+		return false
+	}
+	lines := 0
+	for _, hunk := range file.Hunks {
+		lines += len(hunk.Lines)
+		if lines > r.highlightLimit {
+			return true
+		}
+	}
+	return false
+}
+
+// fileLexer returns the lexer for file, or nil when it is too large to highlight
+func (r *Renderer) fileLexer(file parser.FileDiff) chroma.Lexer {
+	if r.skipsHighlighting(file) {
+		return nil
+	}
+	return r.getLexer(file.Extension)
+}
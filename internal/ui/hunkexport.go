@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// hunkPatchFile is where marked hunks are exported, relative to the working directory
+const hunkPatchFile = "critica.patch"
+
+// currentHunkIndex returns the index of the hunk at the top of the diff view, or -1 when
+// the file shows no hunks
+func (m model) currentHunkIndex() int {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.files) || m.collapsed[m.selectedIdx] {
+		return -1
+	}
+
+	lines, hunkStarts := m.renderDiffLinesWithHunks(m.files[m.selectedIdx])
+	maxScroll := len(lines) - m.diffViewportHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	current := m.scrollOffset
+	if current > maxScroll {
+		current = maxScroll
+	}
+
+	idx := -1
+	for i, start := range hunkStarts {
+		if start > current {
+			break
+		}
+		idx = i
+	}
+	return idx
+}
+
+// toggleHunkMark marks or unmarks the hunk at the top of the diff view for export
+func (m *model) toggleHunkMark() {
+	if m.fullFileView {
+		m.hunkExportMsg = "Leave the full-file view (F) to mark hunks"
+		return
+	}
+	idx := m.currentHunkIndex()
+	if idx < 0 {
+		return
+	}
+
+	path := m.files[m.selectedIdx].NewPath
+	if m.markedHunks == nil {
+		m.markedHunks = make(map[string]map[int]bool)
+	}
+	if m.markedHunks[path] == nil {
+		m.markedHunks[path] = make(map[int]bool)
+	}
+	if m.markedHunks[path][idx] {
+		delete(m.markedHunks[path], idx)
+	} else {
+		m.markedHunks[path][idx] = true
+	}
+	if m.diffLines != nil {
+		// Marked hunks get a marker line
+		m.diffLines.reset()
+	}
+	m.hunkExportMsg = ""
+}
+
+// renderHunkMark renders the line shown at the top of a hunk marked for export
+func renderHunkMark(width int) string {
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("#3fb950")).Bold(true)
+	if width > 0 {
+		style = style.Width(width)
+	}
+	return style.Render("✓ marked for export")
+}
+
+// markedHunkCount returns how many hunks of the file at path are marked for export
+func (m model) markedHunkCount(path string) int {
+	return len(m.markedHunks[path])
+}
+
+// markedPatch serializes the marked hunks of the listed files as a patch, returning the
+// numbers of hunks and files it contains
+func (m model) markedPatch() (string, int, int) {
+	var selected []parser.FileDiff
+	hunks := 0
+	for _, file := range m.files {
+		marked := m.markedHunks[file.NewPath]
+		if len(marked) == 0 {
+			continue
+		}
+		file = selectHunks(file, marked)
+		selected = append(selected, file)
+		hunks += len(file.Hunks)
+	}
+	return parser.FilesToUnified(selected), hunks, len(selected)
+}
+
+// exportMarkedHunks writes the marked hunks to hunkPatchFile
+func (m *model) exportMarkedHunks() {
+	patch, hunks, files := m.markedPatch()
+	if hunks == 0 {
+		m.hunkExportMsg = "No hunks marked: press x on a hunk to mark it"
+		return
+	}
+	if err := os.WriteFile(hunkPatchFile, []byte(patch), 0o644); err != nil {
+		m.hunkExportMsg = fmt.Sprintf("Export failed: %v", err)
+		return
+	}
+	m.hunkExportMsg = fmt.Sprintf("Wrote %s from %s to %s (apply with git apply %s)",
+		plural(hunks, "hunk", "hunks"), plural(files, "file", "files"), hunkPatchFile, hunkPatchFile)
+}
+
+// selectHunks returns file with only the marked hunks. The new-side start of each kept
+// hunk is shifted by the lines the dropped hunks before it would have added or removed,
+// so the patch applies to the old side on its own.
+func selectHunks(file parser.FileDiff, marked map[int]bool) parser.FileDiff {
+	// The patch applies to tracked content; critica's untracked header isn't git syntax
+	file.IsUntracked = false
+
+	var hunks []parser.Hunk
+	shift := 0
+	for i, hunk := range file.Hunks {
+		if !marked[i] {
+			shift += hunkNetLines(hunk)
+			continue
+		}
+		hunk.NewStart -= shift
+		hunks = append(hunks, hunk)
+	}
+	file.Hunks = hunks
+	return file
+}
+
+// hunkNetLines returns the number of lines a hunk adds minus the number it deletes
+func hunkNetLines(hunk parser.Hunk) int {
+	net := 0
+	for _, line := range hunk.Lines {
+		switch line.Type {
+		case parser.LineAdded:
+			net++
+		case parser.LineDeleted:
+			net--
+		}
+	}
+	return net
+}
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// runGit runs git in dir with a fixed identity and returns its output
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=critica", "-c", "user.email=critica@example.com",
+		"-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// writeFiles writes each content to its name in dir, removing the names mapped to ""
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		var err error
+		if content == "" {
+			err = os.Remove(path)
+		} else {
+			err = os.WriteFile(path, []byte(content), 0o644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// exportRepo commits a base version and checks out a change with several hunks that
+// add and remove lines, a rename and a file without a final newline. It returns the
+// repository and the parsed diff of the change.
+func exportRepo(t *testing.T) (string, []parser.FileDiff) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+
+	var base []string
+	for i := 1; i <= 60; i++ {
+		base = append(base, fmt.Sprintf("line %d\n", i))
+	}
+	writeFiles(t, dir, map[string]string{
+		"multi.txt":    strings.Join(base, ""),
+		"old name.txt": "alpha\nbeta\ngamma\ndelta\nepsilon\n",
+		"noeol.txt":    "first\nlast without newline",
+	})
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "base")
+
+	// Each hunk changes the line count, so dropping one shifts the ones after it
+	changed := slices.Clone(base)
+	changed[2] = "added 1\nadded 2\nadded 3\n"
+	changed[25] = ""
+	changed[26] = ""
+	changed[50] = "replaced\nand one more\n"
+	writeFiles(t, dir, map[string]string{
+		"multi.txt":    strings.Join(changed, ""),
+		"old name.txt": "",
+		"new name.txt": "alpha\nbeta\ngamma\ndelta\nepsilon!\n",
+		"noeol.txt":    "first\nnew last without newline",
+	})
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "change")
+
+	diff := runGit(t, dir, "diff", "-M", "--src-prefix=a/", "--dst-prefix=b/", "HEAD~1", "HEAD")
+	files, err := parser.ParseDiff(diff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	runGit(t, dir, "checkout", "-q", "HEAD~1")
+	return dir, files
+}
+
+// TestMarkedPatchApplies checks that every choice of marked hunks exports a patch git
+// applies to the base version
+func TestMarkedPatchApplies(t *testing.T) {
+	dir, files := exportRepo(t)
+
+	var multi parser.FileDiff
+	for _, file := range files {
+		if file.NewPath == "multi.txt" {
+			multi = file
+		}
+	}
+	if len(multi.Hunks) != 3 {
+		t.Fatalf("multi.txt has %d hunks, want 3", len(multi.Hunks))
+	}
+
+	// Every subset of the three hunks, plus the whole rename and no-newline files
+	for mask := 1; mask < 1<<len(multi.Hunks); mask++ {
+		marked := map[string]map[int]bool{
+			"new name.txt": {0: true},
+			"noeol.txt":    {0: true},
+			"multi.txt":    {},
+		}
+		for i := range multi.Hunks {
+			if mask&(1<<i) != 0 {
+				marked["multi.txt"][i] = true
+			}
+		}
+
+		m := model{files: files, markedHunks: marked}
+		patch, hunks, exported := m.markedPatch()
+		if want := 2 + len(marked["multi.txt"]); hunks != want || exported != 3 {
+			t.Errorf("mask %b: exported %d hunks from %d files, want %d from 3", mask, hunks, exported, want)
+		}
+
+		// git apply tolerates offsets, so check the shifted new-side starts directly
+		shift := 0
+		for _, hunk := range selectHunks(multi, marked["multi.txt"]).Hunks {
+			if hunk.NewStart != hunk.OldStart+shift {
+				t.Errorf("mask %b: hunk at -%d starts at +%d, want +%d", mask, hunk.OldStart, hunk.NewStart, hunk.OldStart+shift)
+			}
+			shift += hunkNetLines(hunk)
+		}
+
+		path := filepath.Join(t.TempDir(), hunkPatchFile)
+		if err := os.WriteFile(path, []byte(patch), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cmd := exec.Command("git", "apply", "--check", path)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("mask %b: git apply --check: %v\n%s\npatch:\n%s", mask, err, out, patch)
+		}
+	}
+}
+
+// TestHunkMarkShown checks that a marked hunk gets a marker line at its start in the diff
+// view, and loses it when unmarked
+func TestHunkMarkShown(t *testing.T) {
+	file := longFile(3)
+	second := parser.Hunk{OldStart: 40, OldLines: 1, NewStart: 40, NewLines: 1, Lines: []parser.Line{
+		{Type: parser.LineUnchanged, Content: "unchanged", OldLineNum: 40, NewLineNum: 40},
+	}}
+	file.Hunks = append(file.Hunks, second)
+	m := newTestModel([]parser.FileDiff{file}, RendererOptions{Width: 100}, 100, 30)
+	m = keys(m, "enter")
+
+	markedAt := func() []int {
+		lines, hunkStarts := m.renderDiffLinesWithHunks(m.files[m.selectedIdx])
+		var marked []int
+		for i, start := range hunkStarts {
+			if strings.Contains(lines[start], "marked for export") {
+				marked = append(marked, i)
+			}
+		}
+		return marked
+	}
+
+	if got := markedAt(); len(got) != 0 {
+		t.Fatalf("hunks %v marked before pressing x", got)
+	}
+	m = keys(m, "x")
+	if got := markedAt(); !slices.Equal(got, []int{0}) {
+		t.Errorf("after x marked hunks = %v, want [0]", got)
+	}
+	m = keys(m, "x")
+	if got := markedAt(); len(got) != 0 {
+		t.Errorf("after unmarking marked hunks = %v, want none", got)
+	}
+}
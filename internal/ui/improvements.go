@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/ai"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// lineRange is a half-open range of rendered lines
+type lineRange struct {
+	start, end int
+}
+
+// improveContent renders the improvements view before viewport scrolling, along with the
+// line range of each improvement. final is true for the loading and error screens, which
+// are shown as-is.
+func (m model) improveContent() (string, []lineRange, bool) {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(1, 0)
+
+	b.WriteString(titleStyle.Render("🤖 AI Improvement Suggestions"))
+	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
+
+	if m.aiLoading {
+		loadingStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e")).
+			Margin(1, 0)
+		b.WriteString(loadingStyle.Render("Analyzing for improvements... (esc: cancel)"))
+		return b.String(), nil, true
+	}
+
+	if m.aiError != "" {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#f85149")).
+			Margin(1, 0)
+		b.WriteString(errorStyle.Render("Error: " + m.aiError))
+		b.WriteString("\n\n")
+		helpStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e"))
+		b.WriteString(helpStyle.Render("Press 'r' to retry or 'esc' to go back"))
+		return b.String(), nil, true
+	}
+
+	b.WriteString(m.renderWithheldNote(m.withheldFiles(m.aiScopeFiles())))
+
+	// Display the actual improvements
+	var ranges []lineRange
+	if len(m.aiImprovements) > 0 {
+		for i, improvement := range m.aiImprovements {
+			itemStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#f0f6fc")).
+				Margin(0, 0, 1, 0)
+			marker := "  "
+			if i == m.improveSelectedIdx {
+				itemStyle = itemStyle.Foreground(lipgloss.Color("#58a6ff"))
+				marker = "> "
+			}
+			start := strings.Count(b.String(), "\n")
+			b.WriteString(itemStyle.Render(wrapText(fmt.Sprintf("%s%d. %s", marker, i+1, improvement), m.width)))
+			b.WriteString("\n")
+			ranges = append(ranges, lineRange{start: start, end: strings.Count(b.String(), "\n")})
+		}
+		b.WriteString(m.renderCopyStatus())
+	} else {
+		codeStyle := lipgloss.NewStyle().
+			Background(lipgloss.Color("#21262d")).
+			Padding(1).
+			Margin(1, 0)
+		b.WriteString(codeStyle.Render("No improvement suggestions generated"))
+	}
+
+	return b.String(), ranges, false
+}
+
+// aiViewportHeight returns the number of content lines visible in an AI view
+func (m model) aiViewportHeight() int {
+	// Height minus help and padding
+	viewportHeight := m.height - 4
+	if viewportHeight < 1 {
+		viewportHeight = 10
+	}
+	return viewportHeight
+}
+
+// improvementsSelectable reports whether j/k move the improvement selection instead of scrolling
+func (m model) improvementsSelectable() bool {
+	return m.viewMode == aiImproveView && !m.aiLoading && m.aiError == "" && len(m.aiImprovements) > 0 && !m.showRawResponse
+}
+
+// selectImprovement highlights the idx-th improvement (clamped) and scrolls it into view
+func (m *model) selectImprovement(idx int) {
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(m.aiImprovements) {
+		idx = len(m.aiImprovements) - 1
+	}
+	m.improveSelectedIdx = idx
+	m.copySuccess = false
+
+	_, ranges, _ := m.improveContent()
+	if idx < 0 || idx >= len(ranges) {
+		return
+	}
+	selected := ranges[idx]
+	viewportHeight := m.aiViewportHeight()
+	if selected.start < m.scrollOffset {
+		m.scrollOffset = selected.start
+	} else if selected.end > m.scrollOffset+viewportHeight {
+		m.scrollOffset = selected.end - viewportHeight
+	}
+}
+
+// jumpToImprovement opens the diff view at the file and line the highlighted improvement
+// refers to. It returns false if the improvement has no location in the current files.
+func (m *model) jumpToImprovement() bool {
+	if m.improveSelectedIdx >= len(m.aiImprovements) {
+		return false
+	}
+	path, lineNum, _, ok := ai.ImprovementLocation(m.aiImprovements[m.improveSelectedIdx])
+	if !ok {
+		return false
+	}
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "./"), "b/")
+
+	for fileIdx, file := range m.files {
+		if file.NewPath != path {
+			continue
+		}
+		hunkIdx, lineIdx, ok := findNewLine(file, lineNum)
+		if !ok {
+			return false
+		}
+
+		m.selectedIdx = fileIdx
+		m.viewMode = diffView
+		m.fullFileView = false
+		if m.collapsed[fileIdx] {
+			m.toggleCollapsed(fileIdx)
+		}
+		m.scrollOffset = m.diffRowForLine(file, hunkIdx, lineIdx) - m.diffViewportHeight()/3
+		if m.scrollOffset < 0 {
+			m.scrollOffset = 0
+		}
+		m.clampDiffScroll()
+		return true
+	}
+	return false
+}
+
+// findNewLine locates a line of the new file version within a file's hunks
+func findNewLine(file parser.FileDiff, lineNum int) (int, int, bool) {
+	for hunkIdx, hunk := range file.Hunks {
+		for lineIdx, line := range hunk.Lines {
+			if line.Type != parser.LineDeleted && line.NewLineNum == lineNum {
+				return hunkIdx, lineIdx, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// diffRowForLine returns the rendered row of a hunk line in the diff view by rendering
+// the diff up to that line
+func (m model) diffRowForLine(file parser.FileDiff, hunkIdx, lineIdx int) int {
+	hunks := make([]parser.Hunk, hunkIdx+1)
+	copy(hunks, file.Hunks[:hunkIdx+1])
+	hunks[hunkIdx].Lines = hunks[hunkIdx].Lines[:lineIdx+1]
+	file.Hunks = hunks
+
+	// Built directly: the truncated file would replace the cached lines of the whole one.
+	// The lines end with an empty element after the final newline.
+	lines, _ := m.buildDiffLines(file)
+	return len(lines) - 2
+}
+
+// improveHelp returns the help line for the improvements view
+func (m model) improveHelp() string {
+	if !m.improvementsSelectable() {
+		return "j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry | v: raw response"
+	}
+	return "j/k: select | enter: go to line | y: copy | Y: copy all | s: copy as suggestion | d/u: page | esc: back | r: retry | v: raw response"
+}
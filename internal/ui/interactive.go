@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -23,6 +24,8 @@ type viewMode int
 
 const (
 	maxFileListPathLength = 48
+	autoCollapsedHint     = " · auto-collapsed"
+	searchPlaceholder     = "Search files..."
 )
 
 const (
@@ -38,6 +41,9 @@ const (
 	aiBranchSelectView
 	aiImproveView
 	aiExplainView
+	aiReviewView
+	aiCommitTypeView
+	aiFullReviewView
 )
 
 type fileFilter int
@@ -46,38 +52,90 @@ const (
 	filterAll fileFilter = iota
 	filterStaged
 	filterUnstaged
+	filterUntracked
 )
 
 type model struct {
-	allFiles         []parser.FileDiff
-	stagedFiles      []parser.FileDiff
-	unstagedFiles    []parser.FileDiff
-	files            []parser.FileDiff
-	fileItems        []list.Item
-	list             list.Model
-	textInput        textinput.Model
-	textarea         textarea.Model
-	viewMode         viewMode
-	previousViewMode viewMode // Track previous view for AI menu navigation
-	selectedIdx      int
-	collapsed        map[int]bool
-	filterMode       fileFilter
-	useColor         bool
-	unified          bool
-	width            int
-	height           int
-	renderer         *Renderer
-	scrollOffset     int  // Current scroll position in diff view
-	previewCollapsed bool // Whether the preview pane is collapsed
+	allFiles            []parser.FileDiff
+	stagedFiles         []parser.FileDiff
+	unstagedFiles       []parser.FileDiff
+	untrackedFiles      []parser.FileDiff
+	files               []parser.FileDiff
+	fileItems           []list.Item
+	list                list.Model
+	textInput           textinput.Model
+	textarea            textarea.Model
+	viewMode            viewMode
+	previousViewMode    viewMode // Track previous view for AI menu navigation
+	aiScope             aiScope  // Files analysis, improvements and explanations run on
+	selectedIdx         int
+	collapsed           map[int]bool
+	filterMode          fileFilter
+	useColor            bool
+	unified             bool
+	width               int
+	height              int
+	renderer            *Renderer
+	scrollOffset        int                           // Current scroll position in diff view
+	previewScrollOffset int                           // Scroll position of the preview pane in file list view
+	jumpBuffer          string                        // Digits typed in file list view to jump to the Nth file
+	fullFileView        bool                          // Show the whole file instead of only changed hunks
+	fullFileLoading     bool                          // Full-file diff is being fetched
+	fullFileError       string                        // Error from fetching the full-file diff
+	fullFileHunks       map[fullFileKey][]parser.Hunk // Full-file diffs by path and comparison, fetched on demand
+	previewCollapsed    bool                          // Whether the preview pane is collapsed
+	collapsePaths       []string                      // Globs for files that start collapsed
+	expandedPaths       map[string]bool               // Auto-collapsed files the user has expanded
+	fullPaths           bool                          // Show whole paths instead of abbreviating them
+	fileLabels          bool                          // Prefix files in the list with a language label
+	pathScroll          int                           // Columns full paths are scrolled left in the file list
+	markedHunks         map[string]map[int]bool       // Hunk indexes marked for export, by file path
+	hunkExportMsg       string                        // Result of the last hunk mark or export
 	// AI-related fields
-	aiService      *ai.Service
-	aiResult       *ai.AnalysisResult
-	aiLoading      bool
-	aiError        string
-	aiCommitMsg    string
-	aiPRDesc       string
-	aiImprovements []string
-	aiExplanation  string
+	aiService          *ai.Service
+	aiResult           *ai.AnalysisResult
+	aiLoading          bool
+	aiError            string
+	aiCancel           context.CancelFunc // Cancels the in-flight AI request
+	aiCommitMsg        string
+	aiCommitCandidates []string // Alternative commit messages; aiCommitMsg is the picked one
+	commitCandidateIdx int      // Index of the picked commit message candidate
+	aiPRDesc           string
+	prWithheld         []string // Files of the PR diff withheld from the AI by .criticaignore
+	aiImprovements     []string
+	improveSelectedIdx int // Highlighted improvement, moved with j/k and copied with y or s
+	aiExplanation      string
+	aiRawOutput        bool                     // Show AI text as-is instead of rendered Markdown
+	analysisFilter     analysisFilter           // Sections shown in the analysis view
+	analysisSectionIdx int                      // Section selected in the analysis table of contents
+	analysisFolded     map[analysisSection]bool // Analysis sections folded with enter
+	markdown           *markdownCache           // Shared Markdown renderer for AI views
+	diffLines          *diffLinesCache          // Rendered lines of the file in the diff view
+	movedLines         *movedLinesCache         // Moved-line marks of the last previewed or viewed file
+	// Review comments anchored to diff lines, shown as gutter markers in the diff view
+	aiReviewComments []ai.ReviewComment
+	reviewExpanded   bool
+	// Full review progress and the steps that failed, shown in their report sections
+	fullReviewStep   fullReviewStep
+	fullReviewErrors map[fullReviewStep]string
+	// Last AI response as the model returned it, shown in place of the formatted view with v
+	aiRawResponse   string
+	showRawResponse bool
+	// Repository git runs in for on-demand reads such as full-file diffs, and the commit
+	// --since compares the all-changes diff against
+	repoPath string
+	sinceRef string
+	// Diff source (all, staged or unstaged) the lists were loaded from, switched with S
+	diffSource       git.DiffMode
+	loadDiff         func(git.DiffMode) (DiffSet, error)
+	diffReloading    bool
+	diffReloadTarget git.DiffMode
+	diffReloadSeq    int
+	diffReloadError  string
+	// Commit shown in the file list after it was created, in place of the working tree
+	shownCommit          string
+	committedDiffLoading bool
+	committedDiffError   string
 	// Branch selection fields
 	branches             []string
 	selectedSourceBranch string
@@ -85,24 +143,34 @@ type model struct {
 	branchDiffContent    string
 	copySuccess          bool
 	// Commit workflow fields
-	commitScope           string // "staged" or "all"
+	commitScope           string           // "staged" or "all"
+	commitOptions         ai.CommitOptions // Type/scope picked before generating the message
+	commitTypeIdx         int              // Selected entry in the commit type picker
+	commitScopeEditing    bool             // Commit type picked; entering the optional scope
+	commitNeverAddAll     bool             // Never offer "git add ." when nothing is staged
+	commitStyleExamples   int              // Recent commit subjects passed to the AI as style examples
+	commitFilesCollapsed  bool             // Hide the list of files in the commit scope
 	commitMessageEditable bool
 	commitApplied         bool
 	commitError           string
 	commitPushed          bool
 	pushError             string
+	pushTarget            string          // Remote branch "git push" updates, e.g. origin/main
+	dryRun                bool            // Record git commands instead of running them
+	dryRunLog             *dryRunRecorder // Commands skipped in dry-run mode
 	commitCompleted       bool
 }
 
 type fileItem struct {
 	fullPath    string
 	displayName string
+	label       string // Language label shown before the path, padded to fileLabelWidth
 	status      string
 	index       int
 }
 
 func (f fileItem) FilterValue() string { return f.fullPath }
-func (f fileItem) Title() string       { return f.displayName }
+func (f fileItem) Title() string       { return f.label + f.displayName }
 func (f fileItem) Description() string { return f.status }
 
 type aiMenuItem struct {
@@ -121,22 +189,38 @@ func filterDisplayName(filter fileFilter) string {
 		return "Staged"
 	case filterUnstaged:
 		return "Unstaged"
+	case filterUntracked:
+		return "Untracked"
 	default:
 		return "All"
 	}
 }
 
+// fileStatusLabel describes a file's change in the file list and diff view header. New
+// files are told apart from untracked ones, since only the former are in the index.
+func fileStatusLabel(file parser.FileDiff) string {
+	switch {
+	case file.IsUntracked && file.IsBinary:
+		return "binary (untracked)"
+	case file.IsUntracked && file.ContentOmitted:
+		return "untracked (too large to show)"
+	case file.IsUntracked:
+		return "untracked"
+	case file.IsNew:
+		return "new file (staged)"
+	case file.IsDeleted:
+		return "deleted"
+	case file.IsRenamed:
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
 func buildFileItems(files []parser.FileDiff) []list.Item {
 	items := make([]list.Item, len(files))
 	for i, file := range files {
-		status := "modified"
-		if file.IsNew {
-			status = "new file"
-		} else if file.IsDeleted {
-			status = "deleted"
-		} else if file.IsRenamed {
-			status = "renamed"
-		}
+		status := fileStatusLabel(file)
 
 		displayName := shortenPath(file.NewPath, maxFileListPathLength)
 		items[i] = fileItem{
@@ -150,22 +234,18 @@ func buildFileItems(files []parser.FileDiff) []list.Item {
 }
 
 func buildAIMenuItems() []list.Item {
+	// Reviews of the changes come first, then the text generated from them
 	items := []list.Item{
+		aiMenuItem{
+			title:       "AI Full Review",
+			description: "Run analysis, improvements and explanation into one report (A)",
+			viewMode:    aiFullReviewView,
+		},
 		aiMenuItem{
 			title:       "AI Analysis",
 			description: "Analyze code changes for quality, issues, and improvements (a)",
 			viewMode:    aiAnalysisView,
 		},
-		aiMenuItem{
-			title:       "AI Commit Message",
-			description: "Generate a commit message based on changes (c)",
-			viewMode:    aiCommitView,
-		},
-		aiMenuItem{
-			title:       "AI PR Description",
-			description: "Generate a pull request description (p)",
-			viewMode:    aiPRView,
-		},
 		aiMenuItem{
 			title:       "AI Improvements",
 			description: "Get suggestions for code improvements (i)",
@@ -176,6 +256,21 @@ func buildAIMenuItems() []list.Item {
 			description: "Get an explanation of what changed (e)",
 			viewMode:    aiExplainView,
 		},
+		aiMenuItem{
+			title:       "AI Review",
+			description: "Get inline review comments anchored to diff lines (r)",
+			viewMode:    aiReviewView,
+		},
+		aiMenuItem{
+			title:       "AI Commit Message",
+			description: "Generate a commit message based on changes (c)",
+			viewMode:    aiCommitView,
+		},
+		aiMenuItem{
+			title:       "AI PR Description",
+			description: "Generate a pull request description (p)",
+			viewMode:    aiPRView,
+		},
 	}
 	return items
 }
@@ -237,7 +332,38 @@ func newCustomDelegate() customDelegate {
 	return d
 }
 
-func RunInteractive(allFiles, stagedFiles, unstagedFiles []parser.FileDiff, rendererOpts RendererOptions, aiService *ai.Service) error {
+// InteractiveOptions describes configuration for the interactive session.
+type InteractiveOptions struct {
+	// CollapsePaths lists globs for generated/vendored files that start collapsed
+	CollapsePaths []string
+	// CommitNeverAddAll removes the "add all files" option when nothing is staged
+	CommitNeverAddAll bool
+	// CommitStyleExamples is how many recent commit subjects to pass to the AI as style examples (0 disables)
+	CommitStyleExamples int
+	// SinceRef is the commit --since compares the working tree against, or ""; it replaces
+	// HEAD as the old side of the all-changes diff
+	SinceRef string
+	// RepoPath locates the repository git is run in; "" uses the current directory
+	RepoPath string
+	// DiffMode is the git diff source the session starts with; S switches it
+	DiffMode git.DiffMode
+	// LoadDiff re-runs git for a diff source, for switching sources without restarting;
+	// nil disables switching
+	LoadDiff func(git.DiffMode) (DiffSet, error)
+	// DryRun records stage/commit/push commands instead of running them
+	DryRun bool
+	// FileLabels prefixes each file in the list with a short colored language label
+	FileLabels bool
+	// FullPaths starts the file list with whole paths instead of abbreviated ones
+	FullPaths bool
+	// PreviewCollapsed starts with the preview pane collapsed
+	PreviewCollapsed bool
+	// SaveView, when set, is called on exit with the last unified and preview-collapsed
+	// settings so they can be restored next time
+	SaveView func(unified, previewCollapsed bool) error
+}
+
+func RunInteractive(allFiles, stagedFiles, unstagedFiles, untrackedFiles []parser.FileDiff, rendererOpts RendererOptions, interactiveOpts InteractiveOptions, aiService *ai.Service) error {
 	delegate := newCustomDelegate()
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.Title = "Changed Files"
@@ -256,7 +382,7 @@ func RunInteractive(allFiles, stagedFiles, unstagedFiles []parser.FileDiff, rend
 
 	// Create text input for search
 	ti := textinput.New()
-	ti.Placeholder = "Search files..."
+	ti.Placeholder = searchPlaceholder
 	ti.CharLimit = 50
 
 	// Create textarea for commit message editing
@@ -266,31 +392,79 @@ func RunInteractive(allFiles, stagedFiles, unstagedFiles []parser.FileDiff, rend
 	ta.SetHeight(10)
 
 	m := model{
-		allFiles:         allFiles,
-		stagedFiles:      stagedFiles,
-		unstagedFiles:    unstagedFiles,
-		list:             l,
-		textInput:        ti,
-		textarea:         ta,
-		viewMode:         fileListView,
-		selectedIdx:      -1,
-		aiService:        aiService,
-		filterMode:       filterAll,
-		useColor:         rendererOpts.UseColor,
-		unified:          rendererOpts.Unified,
-		renderer:         NewRenderer(rendererOpts),
-		previewCollapsed: false,
+		allFiles:            allFiles,
+		stagedFiles:         stagedFiles,
+		unstagedFiles:       unstagedFiles,
+		untrackedFiles:      untrackedFiles,
+		list:                l,
+		textInput:           ti,
+		textarea:            ta,
+		viewMode:            fileListView,
+		selectedIdx:         -1,
+		aiService:           aiService,
+		filterMode:          filterAll,
+		useColor:            rendererOpts.UseColor,
+		unified:             rendererOpts.Unified,
+		renderer:            NewRenderer(rendererOpts),
+		previewCollapsed:    interactiveOpts.PreviewCollapsed,
+		collapsePaths:       interactiveOpts.CollapsePaths,
+		expandedPaths:       make(map[string]bool),
+		markdown:            &markdownCache{},
+		diffLines:           &diffLinesCache{},
+		movedLines:          &movedLinesCache{},
+		fullFileHunks:       make(map[fullFileKey][]parser.Hunk),
+		commitNeverAddAll:   interactiveOpts.CommitNeverAddAll,
+		commitStyleExamples: interactiveOpts.CommitStyleExamples,
+		dryRun:              interactiveOpts.DryRun,
+		fullPaths:           interactiveOpts.FullPaths,
+		fileLabels:          interactiveOpts.FileLabels,
+		repoPath:            interactiveOpts.RepoPath,
+		sinceRef:            interactiveOpts.SinceRef,
+		diffSource:          interactiveOpts.DiffMode,
+		loadDiff:            interactiveOpts.LoadDiff,
+	}
+
+	if m.repoPath == "" {
+		m.repoPath = "."
 	}
-
 	m.applyFilter(filterAll)
 
+	if m.dryRun {
+		// The TUI owns the terminal, so record skipped commands for display instead
+		m.dryRunLog = &dryRunRecorder{}
+		git.DryRun = m.dryRunLog
+	}
+
 	p := tea.NewProgram(m, tea.WithAltScreen())
-	_, err := p.Run()
-	return err
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	if interactiveOpts.SaveView != nil {
+		if fm, ok := final.(model); ok {
+			if err := interactiveOpts.SaveView(fm.unified, fm.previewCollapsed); err != nil {
+				return fmt.Errorf("failed to save view preferences: %w", err)
+			}
+		}
+	}
+	return nil
 }
 
 func (m *model) updateListTitle() {
 	label := filterDisplayName(m.filterMode)
+	if m.diffSource != git.DiffModeAll {
+		label = diffSourceName(m.diffSource) + " · " + label
+	}
+	if m.shownCommit != "" {
+		label = "commit " + shortHash(m.shownCommit) + " · S: back to " + diffSourceName(m.diffSource)
+	}
+	switch {
+	case m.diffReloading:
+		label += " · loading " + diffSourceName(m.diffReloadTarget) + "…"
+	case m.diffReloadError != "":
+		label += " · reload failed: " + m.diffReloadError
+	}
 	m.list.Title = fmt.Sprintf("Changed Files (%s)", label)
 }
 
@@ -306,19 +480,34 @@ func (m *model) applyFilter(filter fileFilter) {
 		target = m.stagedFiles
 	case filterUnstaged:
 		target = m.unstagedFiles
+	case filterUntracked:
+		target = m.untrackedFiles
 	default:
 		target = m.allFiles
 	}
 
 	m.filterMode = filter
-	m.files = target
-	m.fileItems = buildFileItems(target)
-	m.collapsed = newCollapsedMap(len(target))
+	m.files = sortCollapsedLast(target, m.collapsePaths)
+	// Hunks differ between the staged, unstaged and combined diffs
+	m.markedHunks = nil
+	m.fullFileView = false
+	m.fileItems = buildFileItems(m.files)
+	m.formatFileItems()
+	m.collapsed = newCollapsedMap(len(m.files))
 	m.scrollOffset = 0
+	m.previewScrollOffset = 0
+
+	// Generated/vendored files start collapsed
+	for i, file := range m.files {
+		if m.isAutoCollapsed(file) {
+			m.collapsed[i] = true
+			m.setAutoCollapsedHint(i, true)
+		}
+	}
 
 	m.list.SetItems(m.fileItems)
 
-	if len(target) == 0 {
+	if len(m.files) == 0 {
 		m.selectedIdx = -1
 		m.list.ResetSelected()
 		m.updateListTitle()
@@ -327,7 +516,7 @@ func (m *model) applyFilter(filter fileFilter) {
 
 	idx := -1
 	if prevPath != "" {
-		idx = findFileIndexByPath(target, prevPath)
+		idx = findFileIndexByPath(m.files, prevPath)
 	}
 	if idx < 0 {
 		idx = 0
@@ -338,6 +527,86 @@ func (m *model) applyFilter(filter fileFilter) {
 	m.updateListTitle()
 }
 
+// setAutoCollapsedHint adds or removes the auto-collapsed hint on a file list item
+func (m *model) setAutoCollapsedHint(idx int, show bool) {
+	if idx < 0 || idx >= len(m.fileItems) {
+		return
+	}
+	item, ok := m.fileItems[idx].(fileItem)
+	if !ok {
+		return
+	}
+	item.status = strings.TrimSuffix(item.status, autoCollapsedHint)
+	if show {
+		item.status += autoCollapsedHint
+	}
+	m.fileItems[idx] = item
+}
+
+// toggleCollapsed collapses or expands a file, tracking expansion of auto-collapsed files
+func (m *model) toggleCollapsed(idx int) {
+	if idx < 0 || idx >= len(m.files) {
+		return
+	}
+	m.collapsed[idx] = !m.collapsed[idx]
+
+	path := m.files[idx].NewPath
+	if !matchesCollapsePath(path, m.collapsePaths) {
+		return
+	}
+	if m.collapsed[idx] {
+		delete(m.expandedPaths, path)
+	} else {
+		m.expandedPaths[path] = true
+	}
+	m.setAutoCollapsedHint(idx, m.collapsed[idx])
+	if len(m.list.Items()) == len(m.fileItems) {
+		m.list.SetItems(m.fileItems)
+	}
+}
+
+// jumpToNumber appends a typed digit to the jump buffer and selects the matching
+// (1-based) entry in the file list, clamped to the list length
+func (m *model) jumpToNumber(digit string) {
+	count := len(m.list.Items())
+	if count == 0 {
+		return
+	}
+
+	buffer := strings.TrimLeft(m.jumpBuffer+digit, "0")
+	n, err := strconv.Atoi(buffer)
+	if err != nil || n == 0 {
+		m.jumpBuffer = ""
+		return
+	}
+	// Start a new number once the typed one can't grow into a valid position
+	if n > count && m.jumpBuffer != "" {
+		buffer = strings.TrimLeft(digit, "0")
+		if n, err = strconv.Atoi(buffer); err != nil || n == 0 {
+			m.jumpBuffer = ""
+			return
+		}
+	}
+	m.jumpBuffer = buffer
+
+	if n > count {
+		n = count
+	}
+	prevIdx := m.listSelectedFileIndex()
+	m.list.Select(n - 1)
+	if m.listSelectedFileIndex() != prevIdx {
+		m.previewScrollOffset = 0
+	}
+}
+
+// jumpHint renders the pending jump number for the file list help line
+func (m model) jumpHint() string {
+	if m.jumpBuffer == "" {
+		return ""
+	}
+	return fmt.Sprintf("go to: %s (enter: open) | ", m.jumpBuffer)
+}
+
 func (m *model) setFilter(filter fileFilter) {
 	if m.filterMode == filter {
 		return
@@ -346,7 +615,7 @@ func (m *model) setFilter(filter fileFilter) {
 }
 
 func (m *model) cycleFilter() {
-	next := fileFilter((int(m.filterMode) + 1) % 3)
+	next := (m.filterMode + 1) % (filterUntracked + 1)
 	m.applyFilter(next)
 }
 
@@ -360,12 +629,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.list.SetSize(msg.Width, msg.Height-4)
+		// Keep the renderer and scroll position in sync with the new size; all
+		// views re-derive their column widths from m.width on the next frame
+		m.renderer.termWidth = msg.Width
+		m.clampDiffScroll()
+		m.refreshPathDisplay()
 		return m, nil
 
 	case tea.KeyMsg:
 		switch m.viewMode {
 		case fileListView:
-			switch msg.String() {
+			key := msg.String()
+			if len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+				m.jumpToNumber(key)
+				return m, nil
+			}
+			if key != "enter" && key != "o" {
+				m.jumpBuffer = ""
+			}
+
+			switch key {
 			case "q", "ctrl+c":
 				return m, tea.Quit
 
@@ -378,22 +661,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case " ":
 				// Toggle preview collapse
 				m.previewCollapsed = !m.previewCollapsed
+				m.refreshPathDisplay()
 				return m, nil
 
 			case "f":
 				m.cycleFilter()
 				return m, nil
 
+			case "S":
+				// Re-run git diff for the next source: all changes, staged, unstaged
+				return m, m.switchDiffSource()
+
+			case "p":
+				m.toggleFullPaths()
+				return m, nil
+
+			case "<":
+				m.scrollPaths(-pathScrollStep)
+				return m, nil
+
+			case ">":
+				m.scrollPaths(pathScrollStep)
+				return m, nil
+
+			case "ctrl+j", "shift+down":
+				// Scroll the preview pane while keeping the list focused
+				m.scrollPreview(1)
+				return m, nil
+
+			case "ctrl+k", "shift+up":
+				m.scrollPreview(-1)
+				return m, nil
+
 			case "a":
 				m.previousViewMode = fileListView
 				m.viewMode = aiMenuView
 				aiMenuItems := buildAIMenuItems()
 				m.list.SetItems(aiMenuItems)
-				m.list.Title = "AI Functions"
+				m.list.Title = m.aiMenuTitle()
 				return m, nil
 
 			case "o", "enter":
 				// Open file in full diff view
+				m.jumpBuffer = ""
 				if len(m.list.Items()) > 0 {
 					selectedItem := m.list.SelectedItem()
 					if selectedItem != nil {
@@ -413,8 +723,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 
 			default:
+				prevIdx := m.listSelectedFileIndex()
 				var cmd tea.Cmd
 				m.list, cmd = m.list.Update(msg)
+				if m.listSelectedFileIndex() != prevIdx {
+					m.previewScrollOffset = 0
+				}
 				return m, cmd
 			}
 
@@ -474,6 +788,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.updateListTitle()
 				return m, nil
 
+			case "s":
+				// Toggle between the open file and all files
+				m.toggleAIScope()
+				return m, nil
+
 			case "c":
 				// Shortcut for commit
 				if m.aiService != nil {
@@ -481,11 +800,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if len(m.stagedFiles) > 0 {
 						// Automatically use staged files
 						m.commitScope = "staged"
-						m.viewMode = aiCommitView
-						m.aiLoading = true
-						m.aiError = ""
-						m.scrollOffset = 0
-						return m, m.generateCommitMessage()
+						m.startCommitTypePicker()
+						return m, nil
 					} else if len(m.allFiles) > 0 {
 						// No staged files, ask to add all
 						m.viewMode = aiCommitScopeView
@@ -546,6 +862,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 
+			case "A":
+				// Shortcut for the full review
+				if m.aiService != nil {
+					return m, m.startFullReview()
+				}
+				return m, nil
+
+			case "r":
+				// Shortcut for review
+				if m.aiService != nil {
+					m.viewMode = aiReviewView
+					m.aiLoading = true
+					m.aiError = ""
+					m.scrollOffset = 0
+					return m, m.reviewChanges()
+				}
+				return m, nil
+
 			case "enter":
 				// Select AI function
 				if len(m.list.Items()) > 0 {
@@ -565,8 +899,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 									if len(m.stagedFiles) > 0 {
 										// Automatically use staged files
 										m.commitScope = "staged"
-										m.viewMode = aiCommitView
-										return m, m.generateCommitMessage()
+										m.startCommitTypePicker()
+										return m, nil
 									} else if len(m.allFiles) > 0 {
 										// No staged files, ask to add all
 										m.viewMode = aiCommitScopeView
@@ -586,6 +920,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 									return m, m.suggestImprovements()
 								case aiExplainView:
 									return m, m.explainChanges()
+								case aiReviewView:
+									return m, m.reviewChanges()
+								case aiFullReviewView:
+									return m, m.startFullReview()
 								}
 							}
 						}
@@ -606,6 +944,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case "esc", "backspace":
 				m.viewMode = fileListView
+				m.fullFileView = false
+				m.hunkExportMsg = ""
 				return m, nil
 
 			case "/":
@@ -624,7 +964,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewMode = aiMenuView
 				aiMenuItems := buildAIMenuItems()
 				m.list.SetItems(aiMenuItems)
-				m.list.Title = "AI Functions"
+				m.list.Title = m.aiMenuTitle()
 				return m, nil
 
 			case "tab":
@@ -632,13 +972,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.renderer.unified = m.unified
 				return m, nil
 
+			case "t":
+				m.renderer.cycleDiffStyle()
+				return m, nil
+
 			case " ":
-				m.collapsed[m.selectedIdx] = !m.collapsed[m.selectedIdx]
+				m.toggleCollapsed(m.selectedIdx)
+				return m, nil
+
+			case "m":
+				// Expand/collapse inline review comments
+				m.reviewExpanded = !m.reviewExpanded
 				return m, nil
 
 			// Vim motions for scrolling within file
 			case "j", "down":
 				m.scrollOffset++
+				m.clampDiffScroll()
 				return m, nil
 
 			case "k", "up":
@@ -650,6 +1000,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "d", "ctrl+d":
 				// Page down
 				m.scrollOffset += m.height / 2
+				m.clampDiffScroll()
 				return m, nil
 
 			case "u", "ctrl+u":
@@ -666,8 +1017,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 
 			case "G":
-				// Go to bottom (will be clamped in render)
+				// Go to bottom
 				m.scrollOffset = 999999
+				m.clampDiffScroll()
+				return m, nil
+
+			case "}", "n":
+				m.jumpToHunk(1)
+				return m, nil
+
+			case "{", "p":
+				m.jumpToHunk(-1)
+				return m, nil
+
+			case "x":
+				m.toggleHunkMark()
+				return m, nil
+
+			case "X":
+				m.exportMarkedHunks()
 				return m, nil
 
 			// Vim motions for file navigation
@@ -675,6 +1043,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.selectedIdx > 0 {
 					m.selectedIdx--
 					m.scrollOffset = 0 // Reset scroll when changing files
+					m.fullFileView = false
 				}
 				return m, nil
 
@@ -682,13 +1051,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.selectedIdx < len(m.files)-1 {
 					m.selectedIdx++
 					m.scrollOffset = 0 // Reset scroll when changing files
+					m.fullFileView = false
 				}
 				return m, nil
+
+			case "F":
+				// Toggle between changed hunks and the whole file
+				return m, m.toggleFullFile()
 			}
 
-		case aiAnalysisView, aiCommitView, aiCommitScopeView, aiPRView, aiBranchSelectView, aiImproveView, aiExplainView:
+		case aiAnalysisView, aiCommitView, aiCommitScopeView, aiPRView, aiBranchSelectView, aiImproveView, aiExplainView, aiReviewView, aiFullReviewView:
 			switch msg.String() {
 			case "q", "ctrl+c":
+				if m.aiLoading && msg.String() == "q" {
+					// Cancel the request instead of quitting
+					m.cancelAIRequest()
+					return m, nil
+				}
+				if m.aiCancel != nil {
+					m.aiCancel()
+				}
 				return m, tea.Quit
 
 			case "enter":
@@ -696,10 +1078,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.viewMode == aiBranchSelectView {
 					return m, m.selectBranch()
 				}
+				// Fold or unfold the selected analysis section
+				if m.viewMode == aiAnalysisView && !m.aiLoading && m.aiResult != nil {
+					m.toggleAnalysisSection()
+					return m, nil
+				}
+				// Open the diff at the highlighted improvement's file and line
+				if m.improvementsSelectable() {
+					m.jumpToImprovement()
+					return m, nil
+				}
 				// For other views, handle normally
 				return m, nil
 
 			case "esc", "backspace":
+				if m.aiLoading {
+					// Cancel the request and stay to show it was canceled
+					m.cancelAIRequest()
+					return m, nil
+				}
 				if m.viewMode == aiCommitScopeView {
 					m.viewMode = aiMenuView
 					return m, nil
@@ -710,25 +1107,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewMode = fileListView
 				m.aiLoading = false
 				m.aiError = ""
+				m.copySuccess = false
 				return m, nil
 
 			case "y":
 				// Add all files and generate commit message
-				if m.viewMode == aiCommitScopeView {
+				if m.viewMode == aiCommitScopeView && !m.commitNeverAddAll {
 					m.commitScope = "all"
-					m.viewMode = aiCommitView
-					m.aiLoading = true
-					m.aiError = ""
-					m.scrollOffset = 0
-					return m, m.generateCommitMessage()
+					m.startCommitTypePicker()
+					return m, nil
 				}
 				// Push branch (Yes) - existing functionality
 				if m.viewMode == aiCommitView && m.commitApplied {
 					return m, m.pushBranch()
 				}
-				// Copy PR description
-				if m.viewMode == aiPRView && m.aiPRDesc != "" {
-					return m, m.copyToClipboard(m.aiPRDesc)
+				// Copy the PR description, analysis, improvements or explanation
+				if !m.aiLoading {
+					if text := m.copyText(); text != "" {
+						return m, m.copyToClipboard(text)
+					}
+				}
+				return m, nil
+
+			case "Y":
+				// Copy every improvement, not just the highlighted one
+				if m.improvementsSelectable() {
+					return m, m.copyToClipboard(numberedList(m.aiImprovements))
 				}
 				return m, nil
 
@@ -752,6 +1156,32 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				// Pick a commit message candidate by number
+				if m.canPickCommitCandidate() {
+					m.selectCommitCandidate(int(msg.String()[0] - '1'))
+				}
+				return m, nil
+
+			case "h", "left":
+				if m.canPickCommitCandidate() {
+					m.selectCommitCandidate(m.commitCandidateIdx - 1)
+				}
+				return m, nil
+
+			case "l", "right":
+				if m.canPickCommitCandidate() {
+					m.selectCommitCandidate(m.commitCandidateIdx + 1)
+				}
+				return m, nil
+
+			case "f":
+				// Show/hide the files in the commit scope
+				if m.viewMode == aiCommitView || m.viewMode == aiCommitScopeView {
+					m.commitFilesCollapsed = !m.commitFilesCollapsed
+				}
+				return m, nil
+
 			case "e":
 				// Edit commit message
 				if m.viewMode == aiCommitView && m.aiCommitMsg != "" {
@@ -762,6 +1192,58 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 
+			case "s":
+				// Cycle which analysis sections are shown
+				if m.viewMode == aiAnalysisView {
+					m.analysisFilter = m.analysisFilter.next()
+					m.analysisSectionIdx = 0
+					m.scrollOffset = 0
+				}
+				// Copy the highlighted improvement as a GitHub suggestion
+				if m.viewMode == aiImproveView && !m.aiLoading && m.improveSelectedIdx < len(m.aiImprovements) {
+					return m, m.copyToClipboard(githubSuggestion(m.files, m.aiImprovements[m.improveSelectedIdx]))
+				}
+				// Review the commit just created in the diff view
+				if m.canShowCommittedDiff() && !m.committedDiffLoading {
+					return m, m.showCommittedDiff()
+				}
+				return m, nil
+
+			case "tab":
+				if m.improvementsSelectable() {
+					m.selectImprovement((m.improveSelectedIdx + 1) % len(m.aiImprovements))
+				}
+				// Jump to the next section of the analysis
+				if m.viewMode == aiAnalysisView && !m.aiLoading && m.aiResult != nil {
+					m.selectAnalysisSection(m.analysisSectionIdx + 1)
+				}
+				return m, nil
+
+			case "shift+tab":
+				if m.improvementsSelectable() {
+					m.selectImprovement((m.improveSelectedIdx + len(m.aiImprovements) - 1) % len(m.aiImprovements))
+				}
+				if m.viewMode == aiAnalysisView && !m.aiLoading && m.aiResult != nil {
+					m.selectAnalysisSection(m.analysisSectionIdx - 1)
+				}
+				return m, nil
+
+			case "v":
+				// Swap the view for the response exactly as the model returned it
+				if showsAIResponse(m.viewMode) {
+					m.showRawResponse = !m.showRawResponse
+					m.scrollOffset = 0
+				}
+				return m, nil
+
+			case "m":
+				// Toggle between rendered Markdown and raw AI output
+				if m.viewMode == aiAnalysisView || m.viewMode == aiPRView || m.viewMode == aiExplainView || m.viewMode == aiFullReviewView {
+					m.aiRawOutput = !m.aiRawOutput
+					m.scrollOffset = 0
+				}
+				return m, nil
+
 			case "r":
 				// Retry AI operation
 				if m.aiService != nil {
@@ -778,12 +1260,20 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, m.suggestImprovements()
 					case aiExplainView:
 						return m, m.explainChanges()
+					case aiReviewView:
+						return m, m.reviewChanges()
+					case aiFullReviewView:
+						return m, m.startFullReview()
 					}
 				}
 				return m, nil
 
 			// Vim motions for scrolling within AI content (only for non-branch selection views)
 			case "j", "down":
+				if m.improvementsSelectable() {
+					m.selectImprovement(m.improveSelectedIdx + 1)
+					return m, nil
+				}
 				if m.viewMode != aiBranchSelectView {
 					m.scrollOffset++
 					return m, nil
@@ -794,6 +1284,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmd
 
 			case "k", "up":
+				if m.improvementsSelectable() {
+					m.selectImprovement(m.improveSelectedIdx - 1)
+					return m, nil
+				}
 				if m.viewMode != aiBranchSelectView {
 					if m.scrollOffset > 0 {
 						m.scrollOffset--
@@ -821,14 +1315,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "g":
 				// Go to top
 				m.scrollOffset = 0
+				if m.improvementsSelectable() {
+					m.selectImprovement(0)
+				}
 				return m, nil
 
 			case "G":
 				// Go to bottom (will be clamped in render)
 				m.scrollOffset = 999999
+				if m.improvementsSelectable() {
+					m.selectImprovement(len(m.aiImprovements) - 1)
+				}
 				return m, nil
 			}
 
+		case aiCommitTypeView:
+			return m.updateCommitType(msg)
+
 		case aiCommitEditView:
 			switch msg.String() {
 			case "q", "ctrl+c":
@@ -841,6 +1344,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "ctrl+s":
 				// Save edited commit message
 				m.aiCommitMsg = m.textarea.Value()
+				if m.commitCandidateIdx < len(m.aiCommitCandidates) {
+					m.aiCommitCandidates[m.commitCandidateIdx] = m.aiCommitMsg
+				}
 				m.viewMode = aiCommitView
 				return m, nil
 
@@ -853,9 +1359,15 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	// Handle AI messages
+	case aiCanceledMsg:
+		// The view was already updated when the request was canceled
+		return m, nil
+
 	case aiAnalysisResultMsg:
 		m.aiLoading = false
 		m.aiResult = msg.result
+		m.aiRawResponse = msg.raw
+		m.resetAnalysisSections()
 		return m, nil
 
 	case aiAnalysisErrorMsg:
@@ -865,7 +1377,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case aiCommitResultMsg:
 		m.aiLoading = false
-		m.aiCommitMsg = msg.commitMsg
+		m.aiCommitCandidates = msg.candidates
+		m.aiRawResponse = msg.raw
+		m.selectCommitCandidate(0)
 		return m, nil
 
 	case aiCommitErrorMsg:
@@ -876,6 +1390,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case aiPRResultMsg:
 		m.aiLoading = false
 		m.aiPRDesc = msg.prDesc
+		m.prWithheld = msg.withheld
+		m.aiRawResponse = msg.raw
 		return m, nil
 
 	case aiPRErrorMsg:
@@ -886,6 +1402,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case aiImproveResultMsg:
 		m.aiLoading = false
 		m.aiImprovements = msg.improvements
+		m.aiRawResponse = msg.raw
+		m.improveSelectedIdx = 0
 		return m, nil
 
 	case aiImproveErrorMsg:
@@ -896,6 +1414,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case aiExplainResultMsg:
 		m.aiLoading = false
 		m.aiExplanation = msg.explanation
+		m.aiRawResponse = msg.raw
 		return m, nil
 
 	case aiExplainErrorMsg:
@@ -903,9 +1422,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.aiError = msg.err
 		return m, nil
 
+	case aiReviewResultMsg:
+		m.aiLoading = false
+		m.aiReviewComments = msg.comments
+		if m.diffLines != nil {
+			// Review markers change the rendered lines
+			m.diffLines.reset()
+		}
+		m.aiRawResponse = msg.raw
+		return m, nil
+
+	case aiReviewErrorMsg:
+		m.aiLoading = false
+		m.aiError = msg.err
+		return m, nil
+
+	case aiFullReviewStepMsg:
+		return m, m.handleFullReviewStep(msg)
+
+	case diffReloadedMsg:
+		m.applyDiffReload(msg)
+		return m, nil
+
+	case committedDiffMsg:
+		m.applyCommittedDiff(msg)
+		return m, nil
+
+	case fullFileResultMsg:
+		m.fullFileLoading = false
+		m.fullFileHunks[msg.key] = msg.hunks
+		return m, nil
+
+	case fullFileErrorMsg:
+		m.fullFileLoading = false
+		m.fullFileView = false
+		m.fullFileError = msg.err
+		return m, nil
+
 	case commitAppliedMsg:
 		m.commitApplied = true
 		m.commitError = ""
+		m.pushTarget = msg.pushTarget
 		return m, nil
 
 	case commitErrorMsg:
@@ -981,6 +1538,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
+	if m.showingRawResponse() {
+		return m.renderRawResponse()
+	}
+
 	switch m.viewMode {
 	case fileListView:
 		return m.renderFileList()
@@ -998,6 +1559,8 @@ func (m model) View() string {
 		return m.renderAICommitScope()
 	case aiCommitEditView:
 		return m.renderAICommitEdit()
+	case aiCommitTypeView:
+		return m.renderAICommitType()
 	case aiPRView:
 		return m.renderAIPR()
 	case aiBranchSelectView:
@@ -1006,6 +1569,10 @@ func (m model) View() string {
 		return m.renderAIImprove()
 	case aiExplainView:
 		return m.renderAIExplain()
+	case aiReviewView:
+		return m.renderAIReview()
+	case aiFullReviewView:
+		return m.renderAIFullReview()
 	default:
 		return ""
 	}
@@ -1043,7 +1610,7 @@ func (m model) renderFileList() string {
 		b.WriteString("\n\n")
 
 		helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-		help := "space: show preview | o/enter: open full view | /: search | tab: toggle view | f: cycle filter | a: AI menu | q: quit"
+		help := m.jumpHint() + "space: show preview | o/enter: open full view | 1-9: jump to file | /: search | tab: toggle view | f: cycle filter | S: switch source | " + m.pathHelp() + "a: AI menu | q: quit"
 		b.WriteString(helpStyle.Render(help))
 		return b.String()
 	}
@@ -1052,10 +1619,7 @@ func (m model) renderFileList() string {
 	var b strings.Builder
 
 	// Calculate widths
-	fileListWidth := 40
-	if m.width < 120 {
-		fileListWidth = m.width / 3
-	}
+	fileListWidth := m.fileListWidth()
 	previewWidth := m.width - fileListWidth - 3 // -3 for separator and padding
 
 	// Get file list content
@@ -1088,7 +1652,7 @@ func (m model) renderFileList() string {
 	}
 
 	// Render side-by-side
-	separator := lipgloss.NewStyle().Foreground(lipgloss.Color("#3a3a3a")).Render("│")
+	separator := lipgloss.NewStyle().Foreground(lipgloss.Color("#3a3a3a")).Render(m.renderer.columnSeparator)
 
 	for i := 0; i < maxLines; i++ {
 		// Left side (file list)
@@ -1119,7 +1683,7 @@ func (m model) renderFileList() string {
 	// Help text
 	b.WriteString("\n")
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	help := "space: hide preview | o/enter: open full view | j/k: navigate | /: search | tab: toggle view | f: cycle filter | a: AI menu | q: quit"
+	help := m.jumpHint() + "space: hide preview | o/enter: open full view | j/k: navigate | 1-9: jump to file | ctrl+j/k: scroll preview | /: search | tab: toggle view | f: cycle filter | S: switch source | " + m.pathHelp() + "a: AI menu | q: quit"
 	b.WriteString(helpStyle.Render(help))
 
 	return b.String()
@@ -1139,91 +1703,152 @@ func (m model) renderPreviewForFile(fileIdx int, width int) []string {
 		Bold(true).
 		Foreground(lipgloss.Color("180")) // Muted tan/gold instead of bright pink
 
-	status := "modified"
-	if file.IsNew {
-		status = "new file"
-	} else if file.IsDeleted {
-		status = "deleted"
-	} else if file.IsRenamed {
-		status = "renamed"
-	}
+	status := fileStatusLabel(file)
 
 	headerWidth := width - len(status) - 2
 	if headerWidth < 10 {
 		headerWidth = maxFileListPathLength
 	}
-	displayPath := shortenPath(file.NewPath, headerWidth)
+	displayPath := m.displayPath(file.NewPath, headerWidth)
 	lines = append(lines, headerStyle.Render(fmt.Sprintf("%s: %s", status, displayPath)))
+	if m.renderer.skipsHighlighting(file) {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(largeFileNote))
+	}
 	lines = append(lines, "")
 
-	// Render diff content
-	lexer := m.renderer.getLexer(file.Extension)
+	if m.collapsed[fileIdx] && m.isAutoCollapsed(file) {
+		hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		lines = append(lines, hintStyle.Render("Auto-collapsed (matches collapse_paths). Press 'o' to open."))
+		return lines
+	}
+
+	// Render only the visible window of the diff content
+	file = m.markMovedLines(file)
+	lexer := m.renderer.fileLexer(file)
 	unchangedLineCounter := 0
-	lineCount := 0
-	maxLines := m.height - 8 // Leave room for header and help
-	if maxLines < 1 {
-		maxLines = 5
+	maxLines := m.previewViewportHeight()
+	totalRows := previewRowCount(file)
+	offset := clampScrollOffset(m.previewScrollOffset, totalRows, maxLines)
+	visible := func(row int) bool {
+		return row >= offset && row < offset+maxLines
 	}
 
+	row := 0
 	for hunkIdx, hunk := range file.Hunks {
 		if hunkIdx > 0 {
-			prevHunk := file.Hunks[hunkIdx-1]
-			prevEnd := prevHunk.OldStart + prevHunk.OldLines - 1
-			currentStart := hunk.OldStart
-			linesSkipped := currentStart - prevEnd - 1
-
-			lines = append(lines, m.renderer.renderSkipSeparator(width, linesSkipped))
-			lineCount++
+			if visible(row) {
+				lines = append(lines, m.renderer.renderSkipSeparator(width, hunkGap(file.Hunks[hunkIdx-1], hunk)))
+			}
+			row++
 		}
 
 		pairs := computeLinePairs(hunk.Lines)
 		for idx, line := range hunk.Lines {
-			if lineCount >= maxLines {
-				lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("... (press 'o' to view full diff)"))
-				return lines
-			}
-
 			useAltStyle := false
 			if line.Type == parser.LineUnchanged {
 				useAltStyle = unchangedLineCounter%2 == 1
 				unchangedLineCounter++
 			}
 
-			renderedLine := m.renderLineDirect(line, lexer, useAltStyle, pairs[idx], width)
-			lines = append(lines, renderedLine)
-			lineCount++
+			if visible(row) {
+				lines = append(lines, m.renderLineDirect(line, lexer, useAltStyle, pairs[idx], width))
+			}
+			row++
+		}
+	}
+
+	if totalRows > maxLines {
+		end := offset + maxLines
+		if end > totalRows {
+			end = totalRows
 		}
+		hint := fmt.Sprintf("... lines %d-%d of %d (ctrl+j/k: scroll preview | o: full diff)", offset+1, end, totalRows)
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(hint))
 	}
 
 	return lines
 }
 
-func shortenPath(path string, max int) string {
-	if max <= 0 {
-		return path
+// previewViewportHeight returns the number of diff rows shown in the preview pane
+func (m model) previewViewportHeight() int {
+	maxLines := m.height - 8 // Leave room for header and help
+	if maxLines < 1 {
+		maxLines = 5
 	}
+	return maxLines
+}
 
-	pathRunes := []rune(path)
-	if len(pathRunes) <= max {
-		return path
+// previewRowCount returns the number of rows the preview renders for a file
+func previewRowCount(file parser.FileDiff) int {
+	rows := 0
+	for hunkIdx, hunk := range file.Hunks {
+		if hunkIdx > 0 {
+			rows++ // Skip separator
+		}
+		rows += len(hunk.Lines)
 	}
+	return rows
+}
 
-	separator := "/"
-	if strings.Contains(path, "\\") && !strings.Contains(path, "/") {
-		separator = "\\"
+// clampScrollOffset limits a scroll offset so the viewport stays within the content
+func clampScrollOffset(offset, totalLines, viewportHeight int) int {
+	maxScroll := totalLines - viewportHeight
+	if maxScroll < 0 {
+		maxScroll = 0
 	}
-
-	prefix := ".../"
-	if separator == "\\" {
-		prefix = "...\\"
+	if offset > maxScroll {
+		offset = maxScroll
 	}
-	prefixRunes := []rune(prefix)
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
 
-	if max <= len(prefixRunes) {
-		return string(pathRunes[len(pathRunes)-max:])
+// scrollPreview moves the preview pane of the selected file by delta rows
+func (m *model) scrollPreview(delta int) {
+	idx := m.listSelectedFileIndex()
+	if idx < 0 || idx >= len(m.files) {
+		m.previewScrollOffset = 0
+		return
+	}
+	total := previewRowCount(m.files[idx])
+	m.previewScrollOffset = clampScrollOffset(m.previewScrollOffset+delta, total, m.previewViewportHeight())
+}
+
+// listSelectedFileIndex returns the file index of the item selected in the list, or -1
+func (m model) listSelectedFileIndex() int {
+	if item, ok := m.list.SelectedItem().(fileItem); ok {
+		return item.index
+	}
+	return -1
+}
+
+func shortenPath(path string, max int) string {
+	if max <= 0 {
+		return path
+	}
+
+	pathRunes := []rune(path)
+	if len(pathRunes) <= max {
+		return path
+	}
+
+	// Windows paths often mix / and \, so split on both and keep each segment's own
+	// separator. The prefix uses the separator in front of the kept segments.
+	segments, separators := splitPathSegments(path)
+
+	separator := "/"
+	if len(separators) > 0 {
+		separator = separators[len(separators)-1]
+	}
+	prefix := "..." + separator
+	prefixRunes := []rune(prefix)
+
+	if max <= len(prefixRunes) {
+		return string(pathRunes[len(pathRunes)-max:])
 	}
 
-	segments := strings.Split(path, separator)
 	if len(segments) <= 1 {
 		tailLen := max - len(prefixRunes)
 		if tailLen <= 0 {
@@ -1232,7 +1857,7 @@ func shortenPath(path string, max int) string {
 		return prefix + string(pathRunes[len(pathRunes)-tailLen:])
 	}
 
-	var selected []string
+	first := len(segments)
 	total := len(prefixRunes)
 	for i := len(segments) - 1; i >= 0; i-- {
 		seg := segments[i]
@@ -1240,16 +1865,17 @@ func shortenPath(path string, max int) string {
 			continue
 		}
 
+		// Count the separators (and skipped empty segments) up to the kept ones
 		segLen := len([]rune(seg))
-		if len(selected) > 0 {
-			segLen += len([]rune(separator))
+		if first < len(segments) {
+			segLen += first - i
 		}
 
-		if total+segLen > max && len(selected) > 0 {
+		if total+segLen > max && first < len(segments) {
 			break
 		}
 
-		selected = append([]string{seg}, selected...)
+		first = i
 		total += segLen
 
 		if total >= max {
@@ -1257,11 +1883,22 @@ func shortenPath(path string, max int) string {
 		}
 	}
 
-	if len(selected) == 0 {
-		selected = []string{segments[len(segments)-1]}
+	if first == len(segments) {
+		first = len(segments) - 1
+	}
+	if first > 0 {
+		prefix = "..." + separators[first-1]
+	}
+
+	var kept strings.Builder
+	for i := first; i < len(segments); i++ {
+		if i > first {
+			kept.WriteString(separators[i-1])
+		}
+		kept.WriteString(segments[i])
 	}
 
-	remainder := strings.Join(selected, separator)
+	remainder := kept.String()
 	available := max - len(prefixRunes)
 	remainderRunes := []rune(remainder)
 	if available > 0 && len(remainderRunes) > available {
@@ -1271,6 +1908,22 @@ func shortenPath(path string, max int) string {
 	return prefix + remainder
 }
 
+// splitPathSegments splits a path on both / and \\. separators[i] is the separator
+// between segments[i] and segments[i+1].
+func splitPathSegments(path string) ([]string, []string) {
+	var segments, separators []string
+	start := 0
+	for i, r := range path {
+		if r == '/' || r == '\\' {
+			segments = append(segments, path[start:i])
+			separators = append(separators, string(r))
+			start = i + 1
+		}
+	}
+	segments = append(segments, path[start:])
+	return segments, separators
+}
+
 // padOrTruncate pads or truncates a string to the specified width
 func (m model) padOrTruncate(s string, width int) string {
 	if width <= 0 {
@@ -1382,6 +2035,10 @@ func (m model) renderSearch() string {
 }
 
 func (m model) renderAIMenu() string {
+	if m.aiService == nil {
+		return m.renderAIUnavailable()
+	}
+
 	var b strings.Builder
 
 	// Show AI menu list
@@ -1390,18 +2047,40 @@ func (m model) renderAIMenu() string {
 
 	// Help text
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	help := "j/k: navigate | enter: select | c/a/p/i/e: shortcuts | esc: back | q: quit"
+	help := "j/k: navigate | enter: select | c/a/p/i/e/r/A: shortcuts | esc: back | q: quit"
+	if m.aiScopeAvailable() {
+		help = "j/k: navigate | enter: select | c/a/p/i/e/r/A: shortcuts | s: analyze/improve/explain this file or all | esc: back | q: quit"
+	}
 	b.WriteString(helpStyle.Render(help))
 
 	return b.String()
 }
 
+// diffLineSummary describes how much of a file the diff view shows, e.g. "showing 40 of
+// 120 lines (80 folded)", so lines hidden between hunks or by collapsing aren't overlooked
+func (m model) diffLineSummary(file parser.FileDiff) string {
+	shown := 0
+	for _, hunk := range file.Hunks {
+		shown += len(hunk.Lines)
+	}
+	folded := foldedLineCount(file)
+	total := shown + folded
+
+	if m.collapsed[m.selectedIdx] {
+		return fmt.Sprintf("showing 0 of %d lines (collapsed)", total)
+	}
+	if folded == 0 {
+		return fmt.Sprintf("showing %d lines, none folded", shown)
+	}
+	return fmt.Sprintf("showing %d of %d lines (%d folded)", shown, total, folded)
+}
+
 func (m model) renderDiff() string {
 	if m.selectedIdx < 0 || m.selectedIdx >= len(m.files) {
 		return "No file selected"
 	}
 
-	file := m.files[m.selectedIdx]
+	file := m.displayedFile(m.files[m.selectedIdx])
 
 	var b strings.Builder
 
@@ -1415,70 +2094,56 @@ func (m model) renderDiff() string {
 	viewMode := "Split View"
 	if m.unified {
 		viewMode = "Unified View"
+	} else if m.renderer.narrowSplit(m.width) {
+		viewMode = "Unified View (too narrow for split)"
+	}
+	if m.fullFileView {
+		viewMode += " · Full File"
+	}
+	if m.useColor {
+		viewMode += " · " + diffStyleName(m.renderer.themeOptions.DiffStyle)
 	}
 
 	titleWidth := m.width - 20
 	if titleWidth < 20 {
 		titleWidth = maxFileListPathLength
 	}
-	titlePath := shortenPath(file.NewPath, titleWidth)
-	title := fmt.Sprintf("%s (%d/%d) - %s", titlePath, m.selectedIdx+1, len(m.files), viewMode)
+	titlePath := m.displayPath(file.NewPath, titleWidth)
+	title := fmt.Sprintf("%s (%d/%d) - %s · %s", titlePath, m.selectedIdx+1, len(m.files), viewMode, m.diffLineSummary(file))
+	if marked := m.markedHunkCount(file.NewPath); marked > 0 {
+		title += fmt.Sprintf(" · %d/%d hunks marked", marked, len(file.Hunks))
+	}
+	if m.renderer.skipsHighlighting(file) {
+		title += " · " + largeFileNote
+	}
 	b.WriteString(titleStyle.Render(title))
-	b.WriteString("\n\n")
+	b.WriteString("\n" + m.renderer.spacer())
+
+	if m.fullFileLoading {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Loading full file..."))
+		b.WriteString("\n")
+	} else if m.fullFileError != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#f85149")).Render("Full file unavailable: " + m.fullFileError))
+		b.WriteString("\n")
+	}
+	if m.hunkExportMsg != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(m.hunkExportMsg))
+		b.WriteString("\n")
+	}
 
 	// Check if collapsed
 	if m.collapsed[m.selectedIdx] {
 		collapsedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-		b.WriteString(collapsedStyle.Render("File collapsed. Press 'space' to expand."))
-	} else {
-		// Render file diff
-		var diffOutput strings.Builder
-
-		// Temporarily capture stdout
-		oldStdout := fmt.Sprint
-		defer func() { _ = oldStdout }()
-
-		// Render hunks
-		lexer := m.renderer.getLexer(file.Extension)
-		unchangedLineCounter := 0
-		for hunkIdx, hunk := range file.Hunks {
-			// Add separator between hunks to show line jumps
-			if hunkIdx > 0 {
-				prevHunk := file.Hunks[hunkIdx-1]
-				// Calculate the line skip
-				prevEnd := prevHunk.OldStart + prevHunk.OldLines - 1
-				currentStart := hunk.OldStart
-				linesSkipped := currentStart - prevEnd - 1
-
-				diffOutput.WriteString(m.renderer.renderSkipSeparator(m.width, linesSkipped))
-				diffOutput.WriteString("\n")
-			}
-
-			if m.unified {
-				pairs := computeLinePairs(hunk.Lines)
-				for idx, line := range hunk.Lines {
-					useAltStyle := false
-					if line.Type == parser.LineUnchanged {
-						useAltStyle = unchangedLineCounter%2 == 1
-						unchangedLineCounter++
-					}
-					diffOutput.WriteString(m.renderLineDirect(line, lexer, useAltStyle, pairs[idx], m.width))
-					diffOutput.WriteString("\n")
-				}
-			} else {
-				diffOutput.WriteString(m.renderHunkSplit(hunk, lexer))
-			}
+		if m.isAutoCollapsed(file) {
+			b.WriteString(collapsedStyle.Render("Auto-collapsed (matches collapse_paths). Press 'space' to expand."))
+		} else {
+			b.WriteString(collapsedStyle.Render("File collapsed. Press 'space' to expand."))
 		}
-
+	} else {
 		// Apply viewport scrolling
-		allLines := strings.Split(diffOutput.String(), "\n")
+		allLines := m.renderDiffLines(file)
 		totalLines := len(allLines)
-
-		// Calculate viewport height (height - title - help - padding)
-		viewportHeight := m.height - 6
-		if viewportHeight < 1 {
-			viewportHeight = 10
-		}
+		viewportHeight := m.diffViewportHeight()
 
 		// Clamp scroll offset
 		maxScroll := totalLines - viewportHeight
@@ -1514,16 +2179,205 @@ func (m model) renderDiff() string {
 		}
 	}
 
-	b.WriteString("\n\n")
+	b.WriteString("\n" + m.renderer.spacer())
 
 	// Help bar
 	helpStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	help := "j/k: scroll | h/l: prev/next file | space: collapse/expand | g/G: top/bottom | ctrl+d/u: page down/up | tab: toggle view | f: cycle filter | a: AI menu | /: search | esc: back | q: quit"
+	help := "j/k: scroll | {/}: prev/next hunk | x: mark hunk | X: export marked | h/l: prev/next file | space: collapse/expand | F: full file | m: review comments | g/G: top/bottom | ctrl+d/u: page down/up | tab: toggle view | t: style | f: cycle filter | a: AI menu | /: search | esc: back | q: quit"
 	b.WriteString(helpStyle.Render(help))
 
 	return b.String()
 }
 
+// renderDiffLines renders the full diff body of a file as lines, sized to the current terminal width
+func (m model) renderDiffLines(file parser.FileDiff) []string {
+	lines, _ := m.renderDiffLinesWithHunks(file)
+	return lines
+}
+
+// renderDiffLinesWithHunks renders the diff body like renderDiffLines and also returns the
+// index of the first rendered line of each hunk. The result is cached until the file,
+// width or style changes, and must not be modified.
+func (m model) renderDiffLinesWithHunks(file parser.FileDiff) ([]string, []int) {
+	if m.diffLines == nil {
+		return m.buildDiffLines(file)
+	}
+
+	key := m.diffLinesKeyFor(file)
+	if !m.diffLines.valid || m.diffLines.key != key {
+		m.diffLines.lines, m.diffLines.hunkStarts = m.buildDiffLines(file)
+		m.diffLines.key = key
+		m.diffLines.valid = true
+	}
+	return m.diffLines.lines, m.diffLines.hunkStarts
+}
+
+// buildDiffLines renders the diff body of a file and the index of each hunk's first line
+func (m model) buildDiffLines(file parser.FileDiff) ([]string, []int) {
+	// Render file diff. Everything is composed into diffOutput; nothing in this
+	// path may write to stdout while the TUI owns the terminal.
+	var diffOutput strings.Builder
+
+	// Reserve a gutter for review markers when this file has review comments. Comments
+	// are anchored to the regular hunks, so they're hidden in the full-file view.
+	var fileComments map[reviewAnchor][]ai.ReviewComment
+	if !m.fullFileView {
+		fileComments = m.reviewCommentsForFile(file.NewPath)
+	}
+	gutterWidth := 0
+	if len(fileComments) > 0 {
+		gutterWidth = reviewGutterWidth
+	}
+	diffWidth := m.width - gutterWidth
+
+	// Marks refer to the regular hunks too
+	var marked map[int]bool
+	if !m.fullFileView {
+		marked = m.markedHunks[file.NewPath]
+	}
+
+	// Render hunks
+	file = m.markMovedLines(file)
+	lexer := m.renderer.fileLexer(file)
+	unchangedLineCounter := 0
+	renderedLines := 0
+	var hunkStarts []int
+	for hunkIdx, hunk := range file.Hunks {
+		// Add separator between hunks to show line jumps
+		if hunkIdx > 0 {
+			linesSkipped := hunkGap(file.Hunks[hunkIdx-1], hunk)
+
+			diffOutput.WriteString(m.renderer.renderSkipSeparator(m.width, linesSkipped))
+			diffOutput.WriteString("\n")
+			renderedLines++
+		}
+
+		// Recorded for every hunk so the starts line up with file.Hunks
+		hunkStarts = append(hunkStarts, renderedLines)
+		if marked[hunkIdx] {
+			diffOutput.WriteString(renderHunkMark(m.width))
+			diffOutput.WriteString("\n")
+			renderedLines++
+		}
+		if len(hunk.Lines) == 0 {
+			continue
+		}
+
+		// Each rendered row corresponds to one line of the hunk
+		var hunkRows []string
+		if m.renderer.useUnified(m.width) {
+			pairs := computeLinePairs(hunk.Lines)
+			for idx, line := range hunk.Lines {
+				useAltStyle := false
+				if line.Type == parser.LineUnchanged {
+					useAltStyle = unchangedLineCounter%2 == 1
+					unchangedLineCounter++
+				}
+				hunkRows = append(hunkRows, m.renderLineDirect(line, lexer, useAltStyle, pairs[idx], diffWidth))
+			}
+		} else {
+			hunkRows = strings.Split(strings.TrimSuffix(m.renderHunkSplit(hunk, lexer, diffWidth), "\n"), "\n")
+		}
+
+		for idx, row := range hunkRows {
+			comments := fileComments[reviewAnchor{hunk: hunkIdx, line: idx}]
+			if gutterWidth > 0 {
+				diffOutput.WriteString(renderReviewGutter(comments))
+			}
+			diffOutput.WriteString(row)
+			diffOutput.WriteString("\n")
+			renderedLines++
+
+			if m.reviewExpanded && len(comments) > 0 {
+				for _, commentLine := range renderReviewCommentLines(comments, m.width) {
+					diffOutput.WriteString(commentLine)
+					diffOutput.WriteString("\n")
+					renderedLines++
+				}
+			}
+		}
+	}
+
+	return strings.Split(diffOutput.String(), "\n"), hunkStarts
+}
+
+// jumpToHunk scrolls the diff view to the start of the next (dir > 0) or previous
+// (dir < 0) hunk, staying put when there is none in that direction
+func (m *model) jumpToHunk(dir int) {
+	if m.selectedIdx < 0 || m.selectedIdx >= len(m.files) || m.collapsed[m.selectedIdx] {
+		return
+	}
+
+	lines, hunkStarts := m.renderDiffLinesWithHunks(m.displayedFile(m.files[m.selectedIdx]))
+	maxScroll := len(lines) - m.diffViewportHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	// Start from the offset actually displayed, which is clamped at the bottom
+	current := m.scrollOffset
+	if current > maxScroll {
+		current = maxScroll
+	}
+
+	target := -1
+	if dir > 0 {
+		for _, start := range hunkStarts {
+			if start > current {
+				target = start
+				break
+			}
+		}
+	} else {
+		for i := len(hunkStarts) - 1; i >= 0; i-- {
+			if hunkStarts[i] < current {
+				target = hunkStarts[i]
+				break
+			}
+		}
+	}
+	if target < 0 {
+		return
+	}
+
+	m.scrollOffset = target
+	if m.scrollOffset > maxScroll {
+		m.scrollOffset = maxScroll
+	}
+}
+
+// diffViewportHeight returns the number of diff lines visible in the diff view
+func (m model) diffViewportHeight() int {
+	// Height minus title, help and padding
+	viewportHeight := m.height - 6
+	if m.renderer.compact {
+		// No blank lines under the title and above the help bar
+		viewportHeight += 2
+	}
+	if viewportHeight < 1 {
+		viewportHeight = 10
+	}
+	return viewportHeight
+}
+
+// clampDiffScroll keeps the diff view scroll offset within the content after a resize
+func (m *model) clampDiffScroll() {
+	if m.viewMode != diffView || m.selectedIdx < 0 || m.selectedIdx >= len(m.files) {
+		return
+	}
+	if m.collapsed[m.selectedIdx] {
+		m.scrollOffset = 0
+		return
+	}
+
+	maxScroll := len(m.renderDiffLines(m.displayedFile(m.files[m.selectedIdx]))) - m.diffViewportHeight()
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.scrollOffset > maxScroll {
+		m.scrollOffset = maxScroll
+	}
+}
+
 func (m model) renderLineDirect(line parser.Line, lexer chroma.Lexer, useAltStyle bool, counterpart string, availableWidth int) string {
 	var lineNum int
 	var prefix string
@@ -1575,19 +2429,22 @@ func (m model) renderLineDirect(line parser.Line, lexer chroma.Lexer, useAltStyl
 		default:
 			lineStyle = lipgloss.NewStyle().Width(width)
 		}
+		if line.Moved {
+			lineStyle = m.renderer.theme.MovedLineStyle.Copy().Width(width)
+		}
 	} else {
 		lineStyle = lipgloss.NewStyle().Width(width)
 	}
 
 	rendered := lineStyle.Render(fullLine)
-	rendered = m.renderer.applyLineBackground(rendered, line.Type, useAltStyle)
+	rendered = m.renderer.applyLineBackground(rendered, line, useAltStyle)
 	return rendered
 }
 
-func (m model) renderHunkSplit(hunk parser.Hunk, lexer chroma.Lexer) string {
+func (m model) renderHunkSplit(hunk parser.Hunk, lexer chroma.Lexer, width int) string {
 	var b strings.Builder
 
-	columnWidth := (m.width - 3) / 2
+	columnWidth := (width - 3) / 2
 	if columnWidth < 40 {
 		columnWidth = 40
 	}
@@ -1615,7 +2472,7 @@ func (m model) renderHunkSplit(hunk parser.Hunk, lexer chroma.Lexer) string {
 			unchangedLineCounter++
 		}
 
-		separator := m.renderer.theme.SeparatorStyle.Render("│")
+		separator := m.renderer.theme.SeparatorStyle.Render(m.renderer.columnSeparator)
 		b.WriteString(fmt.Sprintf("%s %s %s\n", leftLine, separator, rightLine))
 	}
 
@@ -1625,21 +2482,25 @@ func (m model) renderHunkSplit(hunk parser.Hunk, lexer chroma.Lexer) string {
 // AI Command Functions
 
 func (m *model) performAIAnalysis() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
+	files := m.aiContextFiles(m.aiScopeFiles())
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		result, err := m.aiService.AnalyzeDiff(ctx, m.files)
+		result, err := m.aiService.AnalyzeDiff(ctx, files)
 		if err != nil {
-			return aiAnalysisErrorMsg{err.Error()}
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiAnalysisErrorMsg{aiErrorText(err)}
 		}
-		return aiAnalysisResultMsg{result}
+		return aiAnalysisResultMsg{result: result, raw: m.aiService.LastRawResponse()}
 	}
 }
 
 func (m *model) generateCommitMessage() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
 		// Use the appropriate files based on scope
@@ -1650,50 +2511,87 @@ func (m *model) generateCommitMessage() tea.Cmd {
 			filesToUse = m.allFiles // All files
 		}
 
-		commitMsg, err := m.aiService.GenerateCommitMessage(ctx, filesToUse)
+		opts := m.commitOptions
+		if m.commitStyleExamples > 0 {
+			if subjects, err := git.GetRecentCommitSubjects(".", m.commitStyleExamples); err == nil {
+				opts.RecentSubjects = subjects
+			}
+		}
+
+		candidates, err := m.aiService.GenerateCommitMessagesWithOptions(ctx, m.commitContextFiles(filesToUse), commitCandidateCount, opts)
 		if err != nil {
-			return aiCommitErrorMsg{err.Error()}
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiCommitErrorMsg{aiErrorText(err)}
 		}
-		return aiCommitResultMsg{commitMsg}
+		return aiCommitResultMsg{candidates: candidates, raw: m.aiService.LastRawResponse()}
 	}
 }
 
 func (m *model) generatePRDescription() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		prDesc, err := m.aiService.GeneratePRDescription(ctx, m.files)
+		prDesc, err := m.aiService.GeneratePRDescription(ctx, m.aiContextFiles(m.files))
 		if err != nil {
-			return aiPRErrorMsg{err.Error()}
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiPRErrorMsg{aiErrorText(err)}
 		}
-		return aiPRResultMsg{prDesc}
+		return aiPRResultMsg{prDesc: prDesc, withheld: m.aiService.WithheldFiles(m.aiContextFiles(m.files)), raw: m.aiService.LastRawResponse()}
 	}
 }
 
 func (m *model) suggestImprovements() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
+	files := m.aiContextFiles(m.aiScopeFiles())
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		improvements, err := m.aiService.SuggestImprovements(ctx, m.files)
+		improvements, err := m.aiService.SuggestImprovements(ctx, files)
 		if err != nil {
-			return aiImproveErrorMsg{err.Error()}
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiImproveErrorMsg{aiErrorText(err)}
 		}
-		return aiImproveResultMsg{improvements}
+		return aiImproveResultMsg{improvements: improvements, raw: m.aiService.LastRawResponse()}
 	}
 }
 
 func (m *model) explainChanges() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
+	files := m.aiContextFiles(m.aiScopeFiles())
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
-		explanation, err := m.aiService.ExplainChanges(ctx, m.files)
+		explanation, err := m.aiService.ExplainChanges(ctx, files)
 		if err != nil {
-			return aiExplainErrorMsg{err.Error()}
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiExplainErrorMsg{aiErrorText(err)}
 		}
-		return aiExplainResultMsg{explanation}
+		return aiExplainResultMsg{explanation: explanation, raw: m.aiService.LastRawResponse()}
+	}
+}
+
+func (m *model) reviewChanges() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
+	return func() tea.Msg {
+		defer cancel()
+
+		comments, err := m.aiService.ReviewDiff(ctx, m.aiContextFiles(m.files))
+		if err != nil {
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiReviewErrorMsg{aiErrorText(err)}
+		}
+		return aiReviewResultMsg{comments: comments, raw: m.aiService.LastRawResponse()}
 	}
 }
 
@@ -1713,25 +2611,45 @@ func (m *model) applyCommit() tea.Cmd {
 			return commitErrorMsg{err.Error()}
 		}
 
-		return commitAppliedMsg{success: true, message: "Commit created successfully"}
+		// Resolve where "git push" will go so the push prompt can name it
+		pushTarget, _ := git.GetPushTarget(".")
+		return commitAppliedMsg{success: true, message: "Commit created successfully", pushTarget: pushTarget}
 	}
 }
 
+// pushBranch suspends the TUI while git pushes so credential helpers and SSH can
+// prompt on the real terminal
 func (m *model) pushBranch() tea.Cmd {
-	return func() tea.Msg {
-		err := git.PushBranch(".")
-		if err != nil {
+	if m.dryRun {
+		return func() tea.Msg {
+			if err := git.PushBranch("."); err != nil {
+				return pushErrorMsg{err.Error()}
+			}
+			return pushSuccessMsg{success: true, message: "Dry run: branch not pushed"}
+		}
+	}
+
+	cmd, stderr, err := git.PushCommand(".")
+	if err != nil {
+		return func() tea.Msg {
+			return pushErrorMsg{err.Error()}
+		}
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err := git.PushResult(err, stderr.String()); err != nil {
 			return pushErrorMsg{err.Error()}
 		}
 
 		return pushSuccessMsg{success: true, message: "Branch pushed successfully"}
-	}
+	})
 }
 
 // AI Message Types
 
 type aiAnalysisResultMsg struct {
 	result *ai.AnalysisResult
+	raw    string // Response as the model returned it
 }
 
 type aiAnalysisErrorMsg struct {
@@ -1739,7 +2657,8 @@ type aiAnalysisErrorMsg struct {
 }
 
 type aiCommitResultMsg struct {
-	commitMsg string
+	candidates []string
+	raw        string
 }
 
 type aiCommitErrorMsg struct {
@@ -1747,7 +2666,9 @@ type aiCommitErrorMsg struct {
 }
 
 type aiPRResultMsg struct {
-	prDesc string
+	prDesc   string
+	withheld []string // Files left out of the prompt by .criticaignore
+	raw      string
 }
 
 type aiPRErrorMsg struct {
@@ -1756,6 +2677,7 @@ type aiPRErrorMsg struct {
 
 type aiImproveResultMsg struct {
 	improvements []string
+	raw          string
 }
 
 type aiImproveErrorMsg struct {
@@ -1764,19 +2686,30 @@ type aiImproveErrorMsg struct {
 
 type aiExplainResultMsg struct {
 	explanation string
+	raw         string
 }
 
 type aiExplainErrorMsg struct {
 	err string
 }
 
+type aiReviewResultMsg struct {
+	comments []ai.ReviewComment
+	raw      string
+}
+
+type aiReviewErrorMsg struct {
+	err string
+}
+
 type commitScopeSelectedMsg struct {
 	scope string
 }
 
 type commitAppliedMsg struct {
-	success bool
-	message string
+	success    bool
+	message    string
+	pushTarget string
 }
 
 type commitErrorMsg struct {
@@ -1795,161 +2728,17 @@ type pushErrorMsg struct {
 // AI Render Functions
 
 func (m model) renderAIAnalysis() string {
-	var b strings.Builder
-
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#58a6ff")).
-		Margin(1, 0)
-
-	b.WriteString(titleStyle.Render("🤖 AI Analysis Results"))
-	b.WriteString("\n")
-
-	if m.aiLoading {
-		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8b949e")).
-			Margin(1, 0)
-		b.WriteString(loadingStyle.Render("Analyzing changes with AI..."))
-		return b.String()
-	}
-
-	if m.aiError != "" {
-		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f85149")).
-			Margin(1, 0)
-		b.WriteString(errorStyle.Render("Error: " + m.aiError))
-		b.WriteString("\n\n")
-		helpStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8b949e"))
-		b.WriteString(helpStyle.Render("Press 'r' to retry or 'esc' to go back"))
-		return b.String()
-	}
-
-	if m.aiResult == nil {
-		return b.String()
-	}
-
-	// Summary
-	if m.aiResult.Summary != "" {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("📝 Summary:"))
-		b.WriteString("\n")
-		b.WriteString(m.aiResult.Summary)
-		b.WriteString("\n\n")
-	}
-
-	// Code Quality
-	if m.aiResult.CodeQuality != "" {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("🏆 Code Quality:"))
-		b.WriteString("\n")
-		b.WriteString(m.aiResult.CodeQuality)
-		b.WriteString("\n\n")
-	}
-
-	// Issues
-	if len(m.aiResult.Issues) > 0 {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("⚠️  Issues Found:"))
-		b.WriteString("\n")
-		for i, issue := range m.aiResult.Issues {
-			b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, issue))
-		}
-		b.WriteString("\n")
-	}
-
-	// Improvements
-	if len(m.aiResult.Improvements) > 0 {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("💡 Improvement Suggestions:"))
-		b.WriteString("\n")
-		for i, improvement := range m.aiResult.Improvements {
-			b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, improvement))
-		}
-		b.WriteString("\n")
-	}
-
-	// Security Notes
-	if len(m.aiResult.SecurityNotes) > 0 {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("🔒 Security Notes:"))
-		b.WriteString("\n")
-		for i, note := range m.aiResult.SecurityNotes {
-			b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, note))
-		}
-		b.WriteString("\n")
-	}
-
-	// Performance Notes
-	if len(m.aiResult.PerformanceNotes) > 0 {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("⚡ Performance Notes:"))
-		b.WriteString("\n")
-		for i, note := range m.aiResult.PerformanceNotes {
-			b.WriteString(fmt.Sprintf("  %d. %s\n", i+1, note))
-		}
-		b.WriteString("\n")
-	}
-
-	// Commit Message
-	if m.aiResult.CommitMessage != "" {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("📝 Suggested Commit Message:"))
-		b.WriteString("\n")
-		codeStyle := lipgloss.NewStyle().
-			Background(lipgloss.Color("#21262d")).
-			Padding(1).
-			Margin(0, 0, 1, 0)
-		b.WriteString(codeStyle.Render(m.aiResult.CommitMessage))
-		b.WriteString("\n")
-	}
-
-	// PR Description
-	if m.aiResult.PRDescription != "" {
-		sectionStyle := lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#f0f6fc")).
-			Margin(0, 0, 1, 0)
-		b.WriteString(sectionStyle.Render("📋 PR Description:"))
-		b.WriteString("\n")
-		codeStyle := lipgloss.NewStyle().
-			Background(lipgloss.Color("#21262d")).
-			Padding(1).
-			Margin(0, 0, 1, 0)
-		b.WriteString(codeStyle.Render(m.aiResult.PRDescription))
-		b.WriteString("\n")
+	content, _, final := m.analysisContent()
+	if final {
+		return content
 	}
 
 	// Apply viewport scrolling
-	allLines := strings.Split(b.String(), "\n")
+	allLines := strings.Split(content, "\n")
 	totalLines := len(allLines)
 
 	// Calculate viewport height (height - help - padding)
-	viewportHeight := m.height - 4
-	if viewportHeight < 1 {
-		viewportHeight = 10
-	}
+	viewportHeight := m.aiViewportHeight()
 
 	// Clamp scroll offset
 	maxScroll := totalLines - viewportHeight
@@ -1989,7 +2778,7 @@ func (m model) renderAIAnalysis() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#8b949e")).
 		Margin(1, 0)
-	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry")
+	result += helpStyle.Render("j/k: scroll | tab: next section | enter: fold | g/G: top/bottom | d/u: page | s: sections (" + m.analysisFilter.label() + ") | esc: back | r: retry | y: copy | m: " + m.rawToggleLabel() + " | v: raw response")
 
 	return result
 }
@@ -2002,14 +2791,15 @@ func (m model) renderAICommit() string {
 		Foreground(lipgloss.Color("#58a6ff")).
 		Margin(1, 0)
 
-	b.WriteString(titleStyle.Render("🤖 AI Commit Message"))
+	b.WriteString(titleStyle.Render(m.dryRunTitle("🤖 AI Commit Message")))
 	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
 
 	if m.aiLoading {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#8b949e")).
 			Margin(1, 0)
-		b.WriteString(loadingStyle.Render("Generating commit message..."))
+		b.WriteString(loadingStyle.Render("Generating commit message... (esc: cancel)"))
 		return b.String()
 	}
 
@@ -2025,29 +2815,61 @@ func (m model) renderAICommit() string {
 		return b.String()
 	}
 
+	b.WriteString(m.renderWithheldNote(m.withheldFiles(m.commitScopeFiles())))
+
 	// Display the actual commit message
 	if m.aiCommitMsg != "" {
+		if !m.commitApplied {
+			b.WriteString("\n")
+			b.WriteString(m.renderCommitFiles(m.commitScopeFiles()))
+			if len(m.aiCommitCandidates) > 1 {
+				b.WriteString("\n")
+				b.WriteString(m.renderCommitCandidates())
+			}
+		}
+
 		codeStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color("#21262d")).
 			Padding(1).
 			Margin(1, 0)
-		b.WriteString(codeStyle.Render(m.aiCommitMsg))
+		b.WriteString(codeStyle.Render(wrapText(m.aiCommitMsg, m.width-2)))
 		b.WriteString("\n\n")
 
+		// Lint the message; warnings are informational and don't block applying
+		if warnings := ai.ValidateConventionalCommit(m.aiCommitMsg); len(warnings) > 0 && !m.commitApplied {
+			warningStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#d29922"))
+			for _, warning := range warnings {
+				b.WriteString(warningStyle.Render("⚠ " + warning))
+				b.WriteString("\n")
+			}
+			b.WriteString(warningStyle.Render("Press 'e' to edit the message, or 'a' to apply it anyway."))
+			b.WriteString("\n\n")
+		}
+
 		// Show commit status
 		if m.commitApplied {
 			successStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#3fb950")).
 				Bold(true)
-			b.WriteString(successStyle.Render("✅ Commit applied successfully!"))
+			if m.dryRun {
+				b.WriteString(successStyle.Render("✅ Commit applied (dry run)"))
+			} else {
+				b.WriteString(successStyle.Render("✅ Commit applied successfully!"))
+			}
 			b.WriteString("\n\n")
+			b.WriteString(m.renderDryRunLog())
 
 			// Show push status
 			if m.commitPushed {
 				pushSuccessStyle := lipgloss.NewStyle().
 					Foreground(lipgloss.Color("#3fb950")).
 					Bold(true)
-				b.WriteString(pushSuccessStyle.Render("✅ Branch pushed successfully!"))
+				if m.dryRun {
+					b.WriteString(pushSuccessStyle.Render("✅ Branch pushed (dry run)"))
+				} else {
+					b.WriteString(pushSuccessStyle.Render("✅ Branch pushed successfully!"))
+				}
 				b.WriteString("\n\n")
 			} else if m.pushError != "" {
 				pushErrorStyle := lipgloss.NewStyle().
@@ -2066,7 +2888,11 @@ func (m model) renderAICommit() string {
 				questionStyle := lipgloss.NewStyle().
 					Foreground(lipgloss.Color("#f0f6fc")).
 					Bold(true)
-				b.WriteString(questionStyle.Render("Do you want to push branch?"))
+				question := "Do you want to push branch?"
+				if m.pushTarget != "" {
+					question = fmt.Sprintf("Do you want to push branch to %s?", m.pushTarget)
+				}
+				b.WriteString(questionStyle.Render(question))
 				b.WriteString("\n\n")
 
 				optionStyle := lipgloss.NewStyle().
@@ -2077,6 +2903,21 @@ func (m model) renderAICommit() string {
 				b.WriteString(optionStyle.Render("n: No"))
 				b.WriteString("\n\n")
 			}
+
+			// Offer to check what landed in the commit
+			if m.canShowCommittedDiff() {
+				hintStyle := lipgloss.NewStyle().
+					Foreground(lipgloss.Color("#8b949e"))
+				switch {
+				case m.committedDiffLoading:
+					b.WriteString(hintStyle.Render("Loading the committed diff..."))
+				case m.committedDiffError != "":
+					b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#f85149")).Render("❌ Couldn't load the commit: " + m.committedDiffError))
+				default:
+					b.WriteString(hintStyle.Render("s: Show the committed diff"))
+				}
+				b.WriteString("\n\n")
+			}
 		} else if m.commitError != "" {
 			errorStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("#f85149")).
@@ -2091,8 +2932,12 @@ func (m model) renderAICommit() string {
 			b.WriteString(actionStyle.Render("Actions:"))
 			b.WriteString("\n")
 			b.WriteString("  a: Apply commit\n")
-			b.WriteString("  r: Retry (regenerate message)\n")
+			if len(m.aiCommitCandidates) > 1 {
+				b.WriteString("  1-9, h/l: Pick another candidate\n")
+			}
+			b.WriteString("  r: Retry (regenerate messages)\n")
 			b.WriteString("  e: Edit message manually\n")
+			b.WriteString("  f: Show/hide files to commit\n")
 			b.WriteString("\n")
 		}
 	} else {
@@ -2151,7 +2996,7 @@ func (m model) renderAICommit() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#8b949e")).
 		Margin(1, 0)
-	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry")
+	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry | v: raw response")
 
 	return result
 }
@@ -2166,12 +3011,13 @@ func (m model) renderAIPR() string {
 
 	b.WriteString(titleStyle.Render("🤖 AI PR Description"))
 	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
 
 	if m.aiLoading {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#8b949e")).
 			Margin(1, 0)
-		b.WriteString(loadingStyle.Render("Generating PR description..."))
+		b.WriteString(loadingStyle.Render("Generating PR description... (esc: cancel)"))
 		return b.String()
 	}
 
@@ -2187,23 +3033,19 @@ func (m model) renderAIPR() string {
 		return b.String()
 	}
 
+	b.WriteString(m.renderWithheldNote(m.prWithheld))
+
 	// Display the actual PR description
 	if m.aiPRDesc != "" {
 		codeStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color("#21262d")).
 			Padding(1).
 			Margin(1, 0)
-		b.WriteString(codeStyle.Render(m.aiPRDesc))
+		b.WriteString(codeStyle.Render(m.renderAIText(m.aiPRDesc, m.width-2)))
 		b.WriteString("\n\n")
 
 		// Show copy success message
-		if m.copySuccess {
-			successStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#3fb950")).
-				Bold(true)
-			b.WriteString(successStyle.Render("✅ Copied to clipboard!"))
-			b.WriteString("\n\n")
-		}
+		b.WriteString(m.renderCopyStatus())
 	} else {
 		codeStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color("#21262d")).
@@ -2260,12 +3102,68 @@ func (m model) renderAIPR() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#8b949e")).
 		Margin(1, 0)
-	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry | y: copy")
+	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry | y: copy | m: " + m.rawToggleLabel() + " | v: raw response")
 
 	return result
 }
 
 func (m model) renderAIImprove() string {
+	content, _, final := m.improveContent()
+	if final {
+		return content
+	}
+
+	// Apply viewport scrolling
+	allLines := strings.Split(content, "\n")
+	totalLines := len(allLines)
+
+	// Calculate viewport height (height - help - padding)
+	viewportHeight := m.aiViewportHeight()
+
+	// Clamp scroll offset
+	maxScroll := totalLines - viewportHeight
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	scrollOffset := m.scrollOffset
+	if scrollOffset > maxScroll {
+		scrollOffset = maxScroll
+	}
+	if scrollOffset < 0 {
+		scrollOffset = 0
+	}
+
+	// Get visible lines
+	endLine := scrollOffset + viewportHeight
+	if endLine > totalLines {
+		endLine = totalLines
+	}
+
+	visibleLines := allLines[scrollOffset:endLine]
+	result := strings.Join(visibleLines, "\n")
+
+	// Show scroll indicator if needed
+	if totalLines > viewportHeight {
+		result += "\n"
+		scrollInfo := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		percentage := int(float64(scrollOffset) / float64(maxScroll) * 100)
+		if scrollOffset >= maxScroll {
+			percentage = 100
+		}
+		result += scrollInfo.Render(fmt.Sprintf("[%d%%] Line %d-%d of %d", percentage, scrollOffset+1, endLine, totalLines))
+	}
+
+	// Add help text
+	result += "\n\n"
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8b949e")).
+		Margin(1, 0)
+	result += helpStyle.Render(m.improveHelp())
+
+	return result
+}
+
+func (m model) renderAIExplain() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
@@ -2273,14 +3171,15 @@ func (m model) renderAIImprove() string {
 		Foreground(lipgloss.Color("#58a6ff")).
 		Margin(1, 0)
 
-	b.WriteString(titleStyle.Render("🤖 AI Improvement Suggestions"))
+	b.WriteString(titleStyle.Render("🤖 AI Change Explanation"))
 	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
 
 	if m.aiLoading {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#8b949e")).
 			Margin(1, 0)
-		b.WriteString(loadingStyle.Render("Analyzing for improvements..."))
+		b.WriteString(loadingStyle.Render("Explaining changes... (esc: cancel)"))
 		return b.String()
 	}
 
@@ -2296,21 +3195,23 @@ func (m model) renderAIImprove() string {
 		return b.String()
 	}
 
-	// Display the actual improvements
-	if len(m.aiImprovements) > 0 {
-		for i, improvement := range m.aiImprovements {
-			itemStyle := lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#f0f6fc")).
-				Margin(0, 0, 1, 0)
-			b.WriteString(itemStyle.Render(fmt.Sprintf("%d. %s", i+1, improvement)))
-			b.WriteString("\n")
-		}
+	b.WriteString(m.renderWithheldNote(m.withheldFiles(m.aiScopeFiles())))
+
+	// Display the actual explanation
+	if m.aiExplanation != "" {
+		codeStyle := lipgloss.NewStyle().
+			Background(lipgloss.Color("#21262d")).
+			Padding(1).
+			Margin(1, 0)
+		b.WriteString(codeStyle.Render(m.renderAIText(m.aiExplanation, m.width-2)))
+		b.WriteString("\n\n")
+		b.WriteString(m.renderCopyStatus())
 	} else {
 		codeStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color("#21262d")).
 			Padding(1).
 			Margin(1, 0)
-		b.WriteString(codeStyle.Render("No improvement suggestions generated"))
+		b.WriteString(codeStyle.Render("No explanation generated"))
 	}
 
 	// Apply viewport scrolling
@@ -2361,12 +3262,11 @@ func (m model) renderAIImprove() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#8b949e")).
 		Margin(1, 0)
-	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry")
+	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry | y: copy | m: " + m.rawToggleLabel() + " | v: raw response")
 
 	return result
 }
-
-func (m model) renderAIExplain() string {
+func (m model) renderAIReview() string {
 	var b strings.Builder
 
 	titleStyle := lipgloss.NewStyle().
@@ -2374,14 +3274,15 @@ func (m model) renderAIExplain() string {
 		Foreground(lipgloss.Color("#58a6ff")).
 		Margin(1, 0)
 
-	b.WriteString(titleStyle.Render("🤖 AI Change Explanation"))
+	b.WriteString(titleStyle.Render("🤖 AI Review Comments"))
 	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
 
 	if m.aiLoading {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#8b949e")).
 			Margin(1, 0)
-		b.WriteString(loadingStyle.Render("Explaining changes..."))
+		b.WriteString(loadingStyle.Render("Reviewing changes... (esc: cancel)"))
 		return b.String()
 	}
 
@@ -2397,19 +3298,37 @@ func (m model) renderAIExplain() string {
 		return b.String()
 	}
 
-	// Display the actual explanation
-	if m.aiExplanation != "" {
-		codeStyle := lipgloss.NewStyle().
-			Background(lipgloss.Color("#21262d")).
-			Padding(1).
-			Margin(1, 0)
-		b.WriteString(codeStyle.Render(m.aiExplanation))
+	b.WriteString(m.renderWithheldNote(m.withheldFiles(m.files)))
+
+	// Display the comments grouped by file
+	if len(m.aiReviewComments) > 0 {
+		fileStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#f0f6fc"))
+		currentFile := ""
+		for _, comment := range m.aiReviewComments {
+			if comment.File != currentFile {
+				if currentFile != "" {
+					b.WriteString("\n")
+				}
+				b.WriteString(fileStyle.Render("📄 " + comment.File))
+				b.WriteString("\n")
+				currentFile = comment.File
+			}
+			marker := reviewSeverityStyle(comment.Severity).Render(reviewMarker)
+			b.WriteString(wrapText(fmt.Sprintf("  %s L%d %s", marker, comment.Line, comment.Comment), m.width))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#8b949e"))
+		b.WriteString(hintStyle.Render("Open a file's diff to see these comments as gutter markers (m: expand)"))
+		b.WriteString("\n")
 	} else {
 		codeStyle := lipgloss.NewStyle().
 			Background(lipgloss.Color("#21262d")).
 			Padding(1).
 			Margin(1, 0)
-		b.WriteString(codeStyle.Render("No explanation generated"))
+		b.WriteString(codeStyle.Render("No review comments generated"))
 	}
 
 	// Apply viewport scrolling
@@ -2460,10 +3379,11 @@ func (m model) renderAIExplain() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#8b949e")).
 		Margin(1, 0)
-	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry")
+	result += helpStyle.Render("j/k: scroll | g/G: top/bottom | d/u: page | esc: back | r: retry | v: raw response")
 
 	return result
 }
+
 func (m model) renderAICommitScope() string {
 	var b strings.Builder
 
@@ -2472,7 +3392,7 @@ func (m model) renderAICommitScope() string {
 		Foreground(lipgloss.Color("#58a6ff")).
 		Margin(1, 0)
 
-	b.WriteString(titleStyle.Render("🤖 AI Commit Message"))
+	b.WriteString(titleStyle.Render(m.dryRunTitle("🤖 AI Commit Message")))
 	b.WriteString("\n\n")
 
 	// Show warning message
@@ -2483,12 +3403,26 @@ func (m model) renderAICommitScope() string {
 	b.WriteString(warningStyle.Render("No staged files found."))
 	b.WriteString("\n\n")
 
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8b949e")).
+		Margin(1, 0)
+
+	if m.commitNeverAddAll {
+		b.WriteString(warningStyle.Render("Stage the files you want to commit (git add <file>) and try again."))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("commit_never_add_all is enabled · esc: back"))
+		return b.String()
+	}
+
 	// Show question
 	questionStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#f0f6fc")).
 		Bold(true).
 		Margin(0, 0, 1, 0)
 
+	b.WriteString(m.renderCommitFiles(m.allFiles))
+	b.WriteString("\n")
+
 	b.WriteString(questionStyle.Render("Do you want to add all files (git add .)?"))
 	b.WriteString("\n\n")
 
@@ -2503,9 +3437,6 @@ func (m model) renderAICommitScope() string {
 	b.WriteString("\n\n")
 
 	// Help text
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#8b949e")).
-		Margin(1, 0)
 	b.WriteString(helpStyle.Render("Press y or n to select, or esc to go back"))
 
 	return b.String()
@@ -2583,8 +3514,8 @@ func (m *model) selectBranch() tea.Cmd {
 }
 
 func (m *model) generateBranchPRDescription() tea.Cmd {
+	ctx, cancel := m.startAIRequest()
 	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
 		// Validate that branches are set
@@ -2598,6 +3529,9 @@ func (m *model) generateBranchPRDescription() tea.Cmd {
 		// Get diff between branches
 		diffContent, err := git.GetBranchDiff(".", m.selectedSourceBranch, m.selectedTargetBranch)
 		if err != nil {
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
 			return aiPRErrorMsg{err.Error()}
 		}
 
@@ -2609,12 +3543,24 @@ func (m *model) generateBranchPRDescription() tea.Cmd {
 		m.branchDiffContent = diffContent
 
 		// Generate PR description with branch context
-		prDesc, err := m.aiService.GeneratePRDescriptionWithBranches(ctx, diffContent, m.selectedSourceBranch, m.selectedTargetBranch)
+		commitSummary := ""
+		if summary, err := git.GetBranchCommitSummary(".", m.selectedSourceBranch, m.selectedTargetBranch); err == nil {
+			commitSummary = summary.Describe(time.Now())
+		}
+
+		prDesc, err := m.aiService.GeneratePRDescriptionWithBranches(ctx, diffContent, m.selectedSourceBranch, m.selectedTargetBranch, commitSummary)
 		if err != nil {
-			return aiPRErrorMsg{err.Error()}
+			if aiCanceled(ctx) {
+				return aiCanceledMsg{}
+			}
+			return aiPRErrorMsg{aiErrorText(err)}
 		}
 
-		return aiPRResultMsg{prDesc}
+		var withheld []string
+		if files, err := parser.ParseDiff(diffContent); err == nil {
+			withheld = m.aiService.WithheldFiles(files)
+		}
+		return aiPRResultMsg{prDesc: prDesc, withheld: withheld, raw: m.aiService.LastRawResponse()}
 	}
 }
 
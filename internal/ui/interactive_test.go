@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// newTestModel returns a model listing files, sized width x height, as RunInteractive
+// sets it up
+func newTestModel(files []parser.FileDiff, opts RendererOptions, width, height int) model {
+	m := model{
+		allFiles:      files,
+		list:          list.New(nil, newCustomDelegate(), 0, 0),
+		viewMode:      fileListView,
+		selectedIdx:   -1,
+		filterMode:    filterAll,
+		unified:       opts.Unified,
+		renderer:      NewRenderer(opts),
+		expandedPaths: make(map[string]bool),
+		markdown:      &markdownCache{},
+		diffLines:     &diffLinesCache{},
+		movedLines:    &movedLinesCache{},
+		fullFileHunks: make(map[fullFileKey][]parser.Hunk),
+	}
+	m.applyFilter(filterAll)
+	return send(m, tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// send sends msg to m and returns the updated model
+func send(m model, msg tea.Msg) model {
+	updated, _ := m.Update(msg)
+	return updated.(model)
+}
+
+// keys sends each key to m as a key press
+func keys(m model, keys ...string) model {
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		m = send(m, msg)
+	}
+	return m
+}
+
+// longFile returns a modified file with one hunk of n changed lines
+func longFile(n int) parser.FileDiff {
+	hunk := parser.Hunk{OldStart: 1, OldLines: n, NewStart: 1, NewLines: n}
+	for i := 1; i <= n; i++ {
+		hunk.Lines = append(hunk.Lines,
+			parser.Line{Type: parser.LineDeleted, Content: fmt.Sprintf("old line %d", i), OldLineNum: i},
+			parser.Line{Type: parser.LineAdded, Content: fmt.Sprintf("new line %d", i), NewLineNum: i})
+	}
+	return parser.FileDiff{OldPath: "long.go", NewPath: "long.go", Extension: ".go", Hunks: []parser.Hunk{hunk}}
+}
+
+// TestResizeRewrapsDiff checks that a WindowSizeMsg re-derives the diff view's column
+// widths from the new terminal width, in both split and unified views
+func TestResizeRewrapsDiff(t *testing.T) {
+	for _, unified := range []bool{false, true} {
+		t.Run(fmt.Sprintf("unified=%v", unified), func(t *testing.T) {
+			m := newTestModel([]parser.FileDiff{longFile(3)}, RendererOptions{Unified: unified}, 160, 30)
+			m = keys(m, "enter")
+			if m.viewMode != diffView {
+				t.Fatal("enter didn't open the diff view")
+			}
+
+			for _, width := range []int{160, 101, 60, 120} {
+				m = send(m, tea.WindowSizeMsg{Width: width, Height: 30})
+				if m.renderer.termWidth != width {
+					t.Errorf("width %d: renderer width = %d", width, m.renderer.termWidth)
+				}
+
+				// Split columns take half the width each, but no less than 40
+				columnWidth := max((width-3)/2, 40)
+				for _, row := range m.renderDiffLines(m.files[0]) {
+					if row == "" {
+						continue
+					}
+					if unified {
+						if got := lipgloss.Width(row); got != width {
+							t.Errorf("width %d: unified row is %d wide: %q", width, got, row)
+						}
+						continue
+					}
+					left, _, found := strings.Cut(row, "│")
+					if !found {
+						t.Fatalf("width %d: split row has no separator: %q", width, row)
+					}
+					if got := lipgloss.Width(left); got != columnWidth+1 {
+						t.Errorf("width %d: left column is %d wide, want %d: %q", width, got, columnWidth+1, row)
+					}
+					if got := lipgloss.Width(row); got != 2*columnWidth+3 {
+						t.Errorf("width %d: split row is %d wide, want %d: %q", width, got, 2*columnWidth+3, row)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestResizeClampsScroll checks that resizing keeps the diff view's scroll offset within
+// the content, so growing the terminal while scrolled to the bottom doesn't leave the
+// view past the end
+func TestResizeClampsScroll(t *testing.T) {
+	m := newTestModel([]parser.FileDiff{longFile(30)}, RendererOptions{}, 120, 30)
+	m = keys(m, "enter", "G")
+
+	total := len(m.renderDiffLines(m.files[0]))
+	if want := total - m.diffViewportHeight(); m.scrollOffset != want {
+		t.Fatalf("G scrolled to %d, want %d", m.scrollOffset, want)
+	}
+
+	m = send(m, tea.WindowSizeMsg{Width: 120, Height: 50})
+	if want := total - m.diffViewportHeight(); m.scrollOffset != want {
+		t.Errorf("after growing, scroll offset = %d, want %d", m.scrollOffset, want)
+	}
+	if view := m.View(); !strings.Contains(view, "new line 30") {
+		t.Error("the last line isn't visible after growing")
+	}
+
+	// Once everything fits, the view scrolls back to the top
+	m = send(m, tea.WindowSizeMsg{Width: 120, Height: 200})
+	if m.scrollOffset != 0 {
+		t.Errorf("with room for the whole diff, scroll offset = %d, want 0", m.scrollOffset)
+	}
+
+	// Scrolling past the end is clamped too
+	m = send(m, tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = keys(m, "G", "j", "j")
+	if want := total - m.diffViewportHeight(); m.scrollOffset != want {
+		t.Errorf("j past the end scrolled to %d, want %d", m.scrollOffset, want)
+	}
+}
+
+// TestShortenPath checks abbreviated paths, including Windows paths with \ and mixed
+// separators, where each kept segment keeps its own separator
+func TestShortenPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		width int
+		want  string
+	}{
+		{"a/b/c.go", 20, "a/b/c.go"},
+		{"a/b/c.go", 0, "a/b/c.go"},
+		{"internal/ui/renderer.go", 18, ".../ui/renderer.go"},
+		{"a/b/c/d/e.go", 9, ".../e.go"},
+		{`src\internal\ui\renderer.go`, 18, `...\ui\renderer.go`},
+		{`src\internal\ui\renderer.go`, 15, `...\renderer.go`},
+		{`src/internal\ui/renderer.go`, 18, `...\ui/renderer.go`},
+		{`C:\a\b/c\d.go`, 20, `C:\a\b/c\d.go`},
+		{`C:\a\b/c\d.go`, 10, `.../c\d.go`},
+		{`C:\a\b/c\d.go`, 8, `...\d.go`},
+		{`a\\b\c.go`, 8, `...\c.go`},
+		{`dir\averyveryverylongname.go`, 12, `...\gname.go`},
+		{`a\b\c.go`, 3, ".go"},
+	}
+	for _, tt := range tests {
+		got := shortenPath(tt.path, tt.width)
+		if got != tt.want {
+			t.Errorf("shortenPath(%q, %d) = %q, want %q", tt.path, tt.width, got, tt.want)
+		}
+		if tt.width > 0 && len([]rune(got)) > tt.width {
+			t.Errorf("shortenPath(%q, %d) = %q is wider than %d", tt.path, tt.width, got, tt.width)
+		}
+	}
+}
+
+// TestFullFileCachePerFilter checks that a full-file diff loaded under one filter isn't
+// shown for the same file under another, whose sides differ
+func TestFullFileCachePerFilter(t *testing.T) {
+	file := parser.FileDiff{OldPath: "a.go", NewPath: "a.go", Hunks: []parser.Hunk{{
+		OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1,
+		Lines: []parser.Line{{Type: parser.LineAdded, Content: "changed", NewLineNum: 1}},
+	}}}
+	m := newTestModel([]parser.FileDiff{file}, RendererOptions{Width: 80}, 80, 24)
+	m.stagedFiles = []parser.FileDiff{file}
+	m.unstagedFiles = []parser.FileDiff{file}
+
+	m.applyFilter(filterStaged)
+	m.fullFileView = true
+	staged := []parser.Hunk{{Lines: []parser.Line{{Type: parser.LineContext, Content: "staged full file"}}}}
+	m = send(m, fullFileResultMsg{key: m.fullFileKey(file), hunks: staged})
+	if got := m.displayedFile(file).Hunks[0].Lines[0].Content; got != "staged full file" {
+		t.Fatalf("staged full-file diff not shown, got %q", got)
+	}
+
+	m.applyFilter(filterUnstaged)
+	if m.fullFileView {
+		t.Error("full-file view stayed on after switching the filter")
+	}
+	m.fullFileView = true
+	if got := m.displayedFile(file).Hunks[0].Lines[0].Content; got != "changed" {
+		t.Errorf("unstaged filter shows %q, want the file's own hunks until its full-file diff loads", got)
+	}
+}
+
+// TestMarkMovedLinesCache checks that moved lines are marked once per file and marked
+// again when the file's hunks are replaced
+func TestMarkMovedLinesCache(t *testing.T) {
+	files, err := parser.ParseDiff("diff --git a/f.go b/f.go\n--- a/f.go\n+++ b/f.go\n" +
+		"@@ -1,4 +1,1 @@\n-one\n-two\n-three\n keep\n@@ -20,1 +17,4 @@\n end\n+one\n+two\n+three\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestModel(files, RendererOptions{Width: 80, MovedMinLines: 3}, 80, 24)
+	file := files[0]
+
+	marked := m.markMovedLines(file)
+	if !marked.Hunks[0].Lines[0].Moved {
+		t.Fatal("moved block not marked")
+	}
+	if again := m.markMovedLines(file); &again.Hunks[0] != &marked.Hunks[0] {
+		t.Error("moved lines were marked again for the same hunks")
+	}
+
+	reloaded := file
+	reloaded.Hunks = append([]parser.Hunk(nil), file.Hunks...)
+	if again := m.markMovedLines(reloaded); &again.Hunks[0] == &marked.Hunks[0] {
+		t.Error("cached marks were reused for replaced hunks")
+	}
+}
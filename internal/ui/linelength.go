@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// truncateLongLine cuts content to maxLineLength characters, returning the kept text and
+// the number of characters cut, so minified or generated lines aren't highlighted in full
+func (r *Renderer) truncateLongLine(content string) (string, int) {
+	if r.maxLineLength <= 0 || len(content) <= r.maxLineLength {
+		return content, 0
+	}
+	runes := []rune(content)
+	if len(runes) <= r.maxLineLength {
+		return content, 0
+	}
+	return string(runes[:r.maxLineLength]), len(runes) - r.maxLineLength
+}
+
+// overflowMarker renders the note that ends a truncated line
+func (r *Renderer) overflowMarker(overflow int) string {
+	marker := fmt.Sprintf(" … (%d more chars)", overflow)
+	if !r.useColor {
+		return marker
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(marker)
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+)
+
+// markdownCache keeps a glamour renderer for the current width and the output it produced,
+// since View runs on every keypress and building a renderer is comparatively expensive.
+type markdownCache struct {
+	width    int
+	renderer *glamour.TermRenderer
+	rendered map[string]string
+}
+
+// renderMarkdown renders AI text as styled terminal Markdown wrapped to width.
+// It falls back to plain wrapping when the renderer can't be created or fails.
+func (c *markdownCache) renderMarkdown(text string, width int) string {
+	if width < 20 {
+		width = 20
+	}
+
+	if c.renderer == nil || c.width != width {
+		// Use a fixed style: auto-detection queries the terminal, which would interfere with the TUI
+		renderer, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle("dark"),
+			glamour.WithWordWrap(width),
+		)
+		if err != nil {
+			return wrapText(text, width)
+		}
+		c.renderer = renderer
+		c.width = width
+		c.rendered = make(map[string]string)
+	}
+
+	if out, ok := c.rendered[text]; ok {
+		return out
+	}
+
+	out, err := c.renderer.Render(text)
+	if err != nil {
+		return wrapText(text, width)
+	}
+	out = strings.Trim(out, "\n")
+	c.rendered[text] = out
+	return out
+}
+
+// renderAIText renders AI output as Markdown unless the raw toggle is on, in which
+// case only fenced code blocks are highlighted
+func (m model) renderAIText(text string, width int) string {
+	if m.aiRawOutput || m.markdown == nil {
+		return m.renderer.highlightFencedCode(text, width)
+	}
+	return m.markdown.renderMarkdown(text, width)
+}
+
+// rawToggleLabel describes what the raw/Markdown toggle switches to
+func (m model) rawToggleLabel() string {
+	if m.aiRawOutput {
+		return "markdown"
+	}
+	return "raw"
+}
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// RenderNameStatus writes one line per file with its status letter and path, like
+// git diff --name-status: "M path", "A path", "D path" or "R old -> new"
+func (r *Renderer) RenderNameStatus(w io.Writer, files []parser.FileDiff) error {
+	for _, file := range files {
+		status, color := "M", lipgloss.Color("#d29922")
+		path := r.stylePath(file.NewPath)
+		switch {
+		case file.IsNew:
+			status, color = "A", r.theme.AddedFg
+		case file.IsDeleted:
+			status, color = "D", r.theme.DeletedFg
+			path = r.stylePath(file.OldPath)
+		case file.IsRenamed:
+			status, color = "R", lipgloss.Color("#58a6ff")
+			path = r.stylePath(file.OldPath) + " -> " + r.stylePath(file.NewPath)
+		}
+
+		if r.useColor {
+			status = lipgloss.NewStyle().Foreground(color).Bold(true).Render(status)
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", status, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package ui
+
+// pathScrollStep is how many columns < and > scroll full paths in the file list
+const pathScrollStep = 8
+
+// fileListWidth returns the width of the file list column, which takes the whole
+// terminal when the preview pane is collapsed
+func (m model) fileListWidth() int {
+	if m.previewCollapsed {
+		return m.width
+	}
+	if m.width < 120 {
+		return m.width / 3
+	}
+	return 40
+}
+
+// pathColumnWidth returns the room for a path in the file list, inside the item padding
+func (m model) pathColumnWidth() int {
+	width := m.fileListWidth() - 3
+	if m.fileLabels {
+		width -= fileLabelWidth + 1
+	}
+	if width < 10 {
+		return maxFileListPathLength
+	}
+	return width
+}
+
+// displayPath formats a path in the configured path style to fit width: abbreviated
+// with shortenPath, or in full scrolled by pathScroll columns when full paths are toggled on
+func (m model) displayPath(path string, width int) string {
+	path = m.renderer.stylePath(path)
+	if !m.fullPaths {
+		return shortenPath(path, width)
+	}
+	runes := []rune(path)
+	if m.pathScroll <= 0 {
+		return path
+	}
+	if m.pathScroll >= len(runes) {
+		return "…"
+	}
+	return "…" + string(runes[m.pathScroll:])
+}
+
+// scrollPaths scrolls full paths in the file list horizontally by delta columns
+func (m *model) scrollPaths(delta int) {
+	if !m.fullPaths {
+		return
+	}
+	m.pathScroll += delta
+	if m.pathScroll < 0 {
+		m.pathScroll = 0
+	}
+	m.refreshPathDisplay()
+}
+
+// toggleFullPaths switches the file list and diff title between abbreviated and full paths
+func (m *model) toggleFullPaths() {
+	m.fullPaths = !m.fullPaths
+	m.pathScroll = 0
+	m.refreshPathDisplay()
+}
+
+// formatFileItems formats the file items' paths for the current column width and path mode
+func (m *model) formatFileItems() {
+	width := m.pathColumnWidth()
+	for i, item := range m.fileItems {
+		if file, ok := item.(fileItem); ok {
+			file.displayName = m.displayPath(file.fullPath, width)
+			if m.fileLabels {
+				file.label = fileLabelFor(file.fullPath) + " "
+			}
+			m.fileItems[i] = file
+		}
+	}
+}
+
+// refreshPathDisplay re-formats the file list paths, keeping the list's search filter and
+// selection. The list is left alone while it shows the AI menu or branches.
+func (m *model) refreshPathDisplay() {
+	m.formatFileItems()
+
+	if m.viewMode == aiMenuView || m.viewMode == aiBranchSelectView {
+		return
+	}
+	items := m.list.Items()
+	for i, item := range items {
+		if file, ok := item.(fileItem); ok && file.index < len(m.fileItems) {
+			items[i] = m.fileItems[file.index]
+		}
+	}
+	m.list.SetItems(items)
+}
+
+// pathHelp returns the file list help entries for the path display
+func (m model) pathHelp() string {
+	if m.fullPaths {
+		return "p: abbreviate paths | </>: scroll paths | "
+	}
+	return "p: full paths | "
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Path styles accepted by RendererOptions.PathStyle
+const (
+	PathStyleRepo     = "repo"
+	PathStyleRelative = "relative"
+	PathStyleAbsolute = "absolute"
+)
+
+// workingDir returns the current directory with symlinks resolved, so it compares with
+// the repository root git reports; empty when it can't be determined
+func workingDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	return dir
+}
+
+// stylePath rewrites a repo-relative path for display in the configured path style.
+// Paths are left repo-relative when the repository root is unknown.
+func (r *Renderer) stylePath(path string) string {
+	if r.repoRoot == "" || path == "" {
+		return path
+	}
+	full := filepath.Join(r.repoRoot, filepath.FromSlash(path))
+	switch r.pathStyle {
+	case PathStyleAbsolute:
+		return full
+	case PathStyleRelative:
+		if r.workDir == "" {
+			return path
+		}
+		rel, err := filepath.Rel(r.workDir, full)
+		if err != nil {
+			return path
+		}
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"io"
+	"strings"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// RenderPlain writes the diff in git's own unified format, without columns or decoration.
+// With color on, only the code after each +, - or space prefix is syntax highlighted.
+func (r *Renderer) RenderPlain(w io.Writer, files []parser.FileDiff) error {
+	// Untracked files are written as the new files git would show once they're added,
+	// and ones listed without their content like git lists a binary file
+	plainFiles := make([]parser.FileDiff, len(files))
+	for i, file := range files {
+		file.IsUntracked = false
+		if file.ContentOmitted {
+			file.ContentOmitted = false
+			file.IsBinary = true
+		}
+		plainFiles[i] = file
+	}
+	files = plainFiles
+
+	if !r.useColor {
+		_, err := io.WriteString(w, parser.FilesToUnified(files))
+		return err
+	}
+
+	for _, file := range files {
+		lexer := r.fileLexer(file)
+		inHunks := false
+		var b strings.Builder
+		for _, line := range strings.SplitAfter(file.Unified(), "\n") {
+			if strings.HasPrefix(line, "@@") {
+				inHunks = true
+			}
+			if inHunks && line != "" && strings.ContainsRune("+- ", rune(line[0])) {
+				content, newline := strings.CutSuffix(line[1:], "\n")
+				line = line[:1] + r.highlightCode(content, lexer)
+				if newline {
+					line += "\n"
+				}
+			}
+			b.WriteString(line)
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// showsAIResponse reports whether view displays an AI response that v can swap for the
+// response exactly as the model returned it
+func showsAIResponse(view viewMode) bool {
+	switch view {
+	case aiAnalysisView, aiCommitView, aiPRView, aiImproveView, aiExplainView, aiReviewView, aiFullReviewView:
+		return true
+	}
+	return false
+}
+
+// showingRawResponse reports whether the current AI view shows the raw response
+func (m model) showingRawResponse() bool {
+	return m.showRawResponse && showsAIResponse(m.viewMode) && !m.aiLoading
+}
+
+// rawResponseLines returns the raw response split into display rows. Only the escape
+// character is replaced, so a response can't restyle the terminal; the text is otherwise
+// shown exactly as received.
+func (m model) rawResponseLines() []string {
+	text := strings.ReplaceAll(m.aiRawResponse, "\x1b", "␛")
+	return strings.Split(ansi.Hardwrap(text, m.width, false), "\n")
+}
+
+// renderRawResponse renders the last AI response as the model returned it, before
+// parsing, Markdown rendering and wrapping
+func (m model) renderRawResponse() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#58a6ff")).
+		Margin(1, 0)
+	b.WriteString(titleStyle.Render("🤖 Raw AI Response"))
+	b.WriteString("\n")
+	b.WriteString(m.renderAIModel())
+
+	if m.aiRawResponse == "" {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#8b949e")).
+			Margin(1, 0)
+		b.WriteString(emptyStyle.Render("No raw response recorded for this view"))
+	} else {
+		// Leave room for the title above the response
+		viewportHeight := m.aiViewportHeight() - strings.Count(b.String(), "\n")
+		if viewportHeight < 1 {
+			viewportHeight = 1
+		}
+		lines := m.rawResponseLines()
+		offset := clampScrollOffset(m.scrollOffset, len(lines), viewportHeight)
+		end := offset + viewportHeight
+		if end > len(lines) {
+			end = len(lines)
+		}
+		b.WriteString(strings.Join(lines[offset:end], "\n"))
+		if len(lines) > viewportHeight {
+			scrollInfo := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+			b.WriteString("\n")
+			b.WriteString(scrollInfo.Render(fmt.Sprintf("Line %d-%d of %d", offset+1, end, len(lines))))
+		}
+		b.WriteString("\n")
+		b.WriteString(m.renderCopyStatus())
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#8b949e")).
+		Margin(1, 0)
+	b.WriteString(helpStyle.Render("j/k: scroll | g/G: top/bottom | y: copy raw response | v: formatted view | esc: back"))
+
+	return b.String()
+}
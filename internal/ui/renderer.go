@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -9,9 +10,10 @@ import (
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/formatters"
 	"github.com/alecthomas/chroma/v2/lexers"
-	"github.com/alecthomas/chroma/v2/styles"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/danielss-dev/critica/internal/parser"
+	"github.com/muesli/termenv"
 	"golang.org/x/term"
 )
 
@@ -22,6 +24,36 @@ type RendererOptions struct {
 	DiffStyle        string
 	AddedTextColor   string
 	DeletedTextColor string
+	// ForceColor keeps colors when the output is not a terminal, e.g. in CI logs
+	ForceColor bool
+	// Width overrides the detected terminal width when greater than zero
+	Width int
+	// SplitMinWidth renders split diffs unified when the width is below it; 0 disables
+	SplitMinWidth int
+	// MovedMinLines is the shortest block, in non-blank lines, highlighted as moved; 0 disables
+	MovedMinLines int
+	// Wrap continues long lines onto extra rows in the split view instead of truncating them
+	Wrap bool
+	// Compact drops the blank lines around file headers and between hunks
+	Compact bool
+	// MaxLineLength truncates longer lines before highlighting; 0 disables
+	MaxLineLength int
+	// SyntaxStyles maps lowercase language names or extensions to chroma styles used
+	// instead of the default for that language
+	SyntaxStyles map[string]string
+	// HighlightLimit renders files with more diff lines without syntax highlighting; 0 disables
+	HighlightLimit int
+	// SummaryFooter ends the output with a "N files changed" line, like git diff --stat
+	SummaryFooter bool
+	// ColumnSeparator and SkipMarker replace the "│" between split columns and the "⋯"
+	// marking folded lines between hunks; empty keeps the defaults
+	ColumnSeparator string
+	SkipMarker      string
+	// PathStyle shows paths relative to the repository (PathStyleRepo, the default), the
+	// current directory (PathStyleRelative) or as absolute paths (PathStyleAbsolute).
+	// RepoRoot is the repository's top-level directory the other styles start from.
+	PathStyle string
+	RepoRoot  string
 }
 
 // Renderer handles the display of diff output
@@ -30,6 +62,32 @@ type Renderer struct {
 	useColor  bool
 	unified   bool
 	termWidth int
+	// splitMinWidth is the width below which the split view falls back to unified
+	splitMinWidth int
+	// movedMinLines is the threshold passed to parser.MarkMovedLines
+	movedMinLines int
+	// wrap continues long split-view lines onto extra rows within their column
+	wrap bool
+	// compact leaves out the blank spacing lines to fit more diff on small screens
+	compact bool
+	// maxLineLength is the number of characters shown of a line before the rest is cut
+	maxLineLength int
+	// highlightLimit is the number of diff lines above which a file isn't highlighted
+	highlightLimit int
+	// syntaxStyles overrides the chroma style per language
+	syntaxStyles map[string]string
+	// summaryFooter adds a line totalling files, insertions and deletions after the diff
+	summaryFooter bool
+	// columnSeparator divides the split-view columns and must be one column wide
+	columnSeparator string
+	// skipMarker surrounds the count of lines folded between hunks
+	skipMarker string
+	// themeOptions built theme; kept so the diff style can be switched while running
+	themeOptions ThemeOptions
+	// pathStyle, repoRoot and workDir rewrite the repo-relative paths shown to the user
+	pathStyle string
+	repoRoot  string
+	workDir   string
 }
 
 type inlineSegment struct {
@@ -40,39 +98,84 @@ type inlineSegment struct {
 // NewRenderer creates a new renderer
 func NewRenderer(opts RendererOptions) *Renderer {
 	// Get terminal width
-	width, _, err := term.GetSize(int(os.Stdout.Fd()))
-	if err != nil || width == 0 {
-		width = 120 // Default width
+	width := opts.Width
+	if width <= 0 {
+		termWidth, _, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil || termWidth == 0 {
+			termWidth = 120 // Default width
+		}
+		width = termWidth
 	}
 
-	theme := NewTheme(ThemeOptions{
+	themeOptions := ThemeOptions{
 		DiffStyle:        opts.DiffStyle,
 		AddedTextColor:   opts.AddedTextColor,
 		DeletedTextColor: opts.DeletedTextColor,
-	})
+	}
+	theme := NewTheme(themeOptions)
 	if !opts.UseColor {
 		theme = NoColorTheme()
-	}
-
-	return &Renderer{
-		theme:     theme,
-		useColor:  opts.UseColor,
-		unified:   opts.Unified,
-		termWidth: width,
-	}
+	} else if opts.ForceColor {
+		// lipgloss drops styles when stdout is not a terminal unless told otherwise
+		lipgloss.SetColorProfile(termenv.TrueColor)
+	}
+
+	r := &Renderer{
+		theme:           theme,
+		themeOptions:    themeOptions,
+		useColor:        opts.UseColor,
+		unified:         opts.Unified,
+		termWidth:       width,
+		splitMinWidth:   opts.SplitMinWidth,
+		movedMinLines:   opts.MovedMinLines,
+		wrap:            opts.Wrap,
+		compact:         opts.Compact,
+		maxLineLength:   opts.MaxLineLength,
+		highlightLimit:  opts.HighlightLimit,
+		syntaxStyles:    opts.SyntaxStyles,
+		summaryFooter:   opts.SummaryFooter,
+		columnSeparator: opts.ColumnSeparator,
+		skipMarker:      opts.SkipMarker,
+	}
+	if r.columnSeparator == "" {
+		r.columnSeparator = "│"
+	}
+	if r.skipMarker == "" {
+		r.skipMarker = "⋯"
+	}
+	if opts.PathStyle != "" && opts.PathStyle != PathStyleRepo {
+		r.pathStyle = opts.PathStyle
+		r.repoRoot = opts.RepoRoot
+		r.workDir = workingDir()
+	}
+	return r
 }
 
+// computeLinePairs maps each changed line to the counterpart its inline highlighting is
+// computed against: within a run of deleted lines followed by a run of added lines, the
+// k-th deleted line is paired with the k-th added line
 func computeLinePairs(lines []parser.Line) map[int]string {
 	pairs := make(map[int]string)
 
-	for i := 0; i < len(lines)-1; i++ {
-		current := lines[i]
-		next := lines[i+1]
-		if current.Type == parser.LineDeleted && next.Type == parser.LineAdded {
-			pairs[i] = next.Content
-			pairs[i+1] = current.Content
+	for i := 0; i < len(lines); {
+		if lines[i].Type != parser.LineDeleted {
+			i++
+			continue
+		}
+
+		deletedStart := i
+		for i < len(lines) && lines[i].Type == parser.LineDeleted {
 			i++
 		}
+		addedStart := i
+		for i < len(lines) && lines[i].Type == parser.LineAdded {
+			i++
+		}
+
+		for k := 0; deletedStart+k < addedStart && addedStart+k < i; k++ {
+			pairs[deletedStart+k] = lines[addedStart+k].Content
+			pairs[addedStart+k] = lines[deletedStart+k].Content
+		}
 	}
 
 	return pairs
@@ -146,55 +249,113 @@ func commonSuffixLength(a, b []rune) int {
 	return count
 }
 
-// Render displays the diff for all files
-func (r *Renderer) Render(files []parser.FileDiff) {
+// useUnified reports whether diffs are rendered unified at the given width: either the
+// unified view was chosen, or the width is too narrow for the split view
+func (r *Renderer) useUnified(width int) bool {
+	return r.unified || r.narrowSplit(width)
+}
+
+// narrowSplit reports whether the split view would be too narrow at the given width
+func (r *Renderer) narrowSplit(width int) bool {
+	return r.splitMinWidth > 0 && width < r.splitMinWidth
+}
+
+// Render displays the diff for all files on stdout
+func (r *Renderer) Render(files []parser.FileDiff) error {
+	return r.RenderTo(os.Stdout, files)
+}
+
+// RenderString renders the diff for all files and returns it as a string
+func (r *Renderer) RenderString(files []parser.FileDiff) string {
+	var b strings.Builder
+	// Writing to a strings.Builder never fails
+	_ = r.RenderTo(&b, files)
+	return b.String()
+}
+
+// RenderTo writes the diff for all files to w
+func (r *Renderer) RenderTo(w io.Writer, files []parser.FileDiff) error {
 	for i, file := range files {
 		if i > 0 {
-			fmt.Println() // Space between files
+			// Space between files
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if err := r.renderFile(w, file); err != nil {
+			return err
 		}
-		r.renderFile(file)
 	}
+
+	if r.summaryFooter && len(files) > 0 {
+		if _, err := fmt.Fprintln(w, r.formatSummaryFooter(files)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // renderFile renders a single file diff
-func (r *Renderer) renderFile(file parser.FileDiff) {
+func (r *Renderer) renderFile(w io.Writer, file parser.FileDiff) error {
+	file = parser.MarkMovedLines(file, r.movedMinLines)
+
 	// Print file header
 	header := r.formatFileHeader(file)
-	fmt.Println(header)
-	fmt.Println()
+	if _, err := fmt.Fprintf(w, "%s\n%s", header, r.spacer()); err != nil {
+		return err
+	}
 
 	// Get lexer for syntax highlighting
-	lexer := r.getLexer(file.Extension)
+	lexer := r.fileLexer(file)
 
 	// Render each hunk
 	for hunkIdx, hunk := range file.Hunks {
 		// Add separator between hunks to show line jumps
 		if hunkIdx > 0 {
-			prevHunk := file.Hunks[hunkIdx-1]
-
-			// Calculate the line skip
-			// Previous hunk ends at: OldStart + OldLines - 1
-			prevEnd := prevHunk.OldStart + prevHunk.OldLines - 1
-			// Current hunk starts at: OldStart
-			currentStart := hunk.OldStart
-			linesSkipped := currentStart - prevEnd - 1
+			linesSkipped := hunkGap(file.Hunks[hunkIdx-1], hunk)
 
-			fmt.Println(r.renderSkipSeparator(r.termWidth, linesSkipped))
+			if _, err := fmt.Fprintln(w, r.renderSkipSeparator(r.termWidth, linesSkipped)); err != nil {
+				return err
+			}
 		}
 
-		if r.unified {
-			r.renderHunkUnified(hunk, lexer)
+		var err error
+		if r.useUnified(r.termWidth) {
+			err = r.renderHunkUnified(w, hunk, lexer)
 		} else {
-			r.renderHunk(hunk, lexer)
+			err = r.renderHunk(w, hunk, lexer)
 		}
-		fmt.Println() // Space between hunks
+		if err != nil {
+			return err
+		}
+
+		// Space between hunks
+		if _, err := fmt.Fprint(w, r.spacer()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// spacer returns the blank line that separates headers and hunks, or nothing in compact mode
+func (r *Renderer) spacer() string {
+	if r.compact {
+		return ""
 	}
+	return "\n"
 }
 
 // formatFileHeader creates the file header display
 func (r *Renderer) formatFileHeader(file parser.FileDiff) string {
 	var status string
 	switch {
+	case file.IsUntracked && file.IsBinary:
+		status = "binary (untracked)"
+	case file.IsUntracked && file.ContentOmitted:
+		status = "untracked (too large to show)"
+	case file.IsUntracked:
+		status = "untracked"
 	case file.IsNew:
 		status = "new file"
 	case file.IsDeleted:
@@ -205,7 +366,11 @@ func (r *Renderer) formatFileHeader(file parser.FileDiff) string {
 		status = "modified"
 	}
 
-	headerText := fmt.Sprintf(" %s: %s ", status, file.NewPath)
+	path := r.stylePath(file.NewPath)
+	headerText := fmt.Sprintf(" %s: %s ", status, path)
+	if r.skipsHighlighting(file) {
+		headerText = fmt.Sprintf(" %s: %s · %s ", status, path, largeFileNote)
+	}
 
 	if r.useColor {
 		return r.theme.FileHeaderStyle.Render(headerText)
@@ -214,7 +379,7 @@ func (r *Renderer) formatFileHeader(file parser.FileDiff) string {
 }
 
 // renderHunk renders a single hunk in split-screen format
-func (r *Renderer) renderHunk(hunk parser.Hunk, lexer chroma.Lexer) {
+func (r *Renderer) renderHunk(w io.Writer, hunk parser.Hunk, lexer chroma.Lexer) error {
 	// Calculate column width (split screen)
 	columnWidth := (r.termWidth - 3) / 2 // -3 for separator and padding
 	if columnWidth < 40 {
@@ -229,42 +394,51 @@ func (r *Renderer) renderHunk(hunk parser.Hunk, lexer chroma.Lexer) {
 
 	for idx, line := range hunk.Lines {
 		pair := pairs[idx]
-		leftLine := ""
-		rightLine := ""
+		var left, right []string
 		useAltStyle := false
 
 		switch line.Type {
 		case parser.LineDeleted:
 			// Show on left only
-			leftLine = r.formatLine(line, columnWidth, lexer, true, false, pair)
-			rightLine = r.formatEmptyLine(columnWidth)
+			left = r.formatLineRows(line, columnWidth, lexer, true, false, pair, r.wrap)
 
 		case parser.LineAdded:
 			// Show on right only
-			leftLine = r.formatEmptyLine(columnWidth)
-			rightLine = r.formatLine(line, columnWidth, lexer, false, false, pair)
+			right = r.formatLineRows(line, columnWidth, lexer, false, false, pair, r.wrap)
 
 		case parser.LineUnchanged:
 			// Show on both sides with alternating style
 			useAltStyle = unchangedLineCounter%2 == 1
-			leftLine = r.formatLine(line, columnWidth, lexer, true, useAltStyle, "")
-			rightLine = r.formatLine(line, columnWidth, lexer, false, useAltStyle, "")
+			left = r.formatLineRows(line, columnWidth, lexer, true, useAltStyle, "", r.wrap)
+			right = r.formatLineRows(line, columnWidth, lexer, false, useAltStyle, "", r.wrap)
 			unchangedLineCounter++
 		}
 
-		leftLines = append(leftLines, leftLine)
-		rightLines = append(rightLines, rightLine)
+		// Pad the shorter column so wrapped rows stay side by side
+		for len(left) < len(right) {
+			left = append(left, r.formatEmptyLine(columnWidth))
+		}
+		for len(right) < len(left) {
+			right = append(right, r.formatEmptyLine(columnWidth))
+		}
+
+		leftLines = append(leftLines, left...)
+		rightLines = append(rightLines, right...)
 	}
 
 	// Print split-screen output
-	separator := r.theme.SeparatorStyle.Render("│")
+	separator := r.theme.SeparatorStyle.Render(r.columnSeparator)
 	for i := 0; i < len(leftLines); i++ {
-		fmt.Printf("%s %s %s\n", leftLines[i], separator, rightLines[i])
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", leftLines[i], separator, rightLines[i]); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // renderHunkUnified renders a single hunk in unified diff format
-func (r *Renderer) renderHunkUnified(hunk parser.Hunk, lexer chroma.Lexer) {
+func (r *Renderer) renderHunkUnified(w io.Writer, hunk parser.Hunk, lexer chroma.Lexer) error {
 	unchangedLineCounter := 0
 	pairs := computeLinePairs(hunk.Lines)
 
@@ -305,6 +479,9 @@ func (r *Renderer) renderHunkUnified(hunk parser.Hunk, lexer chroma.Lexer) {
 			case parser.LineAdded:
 				lineNumColor = r.theme.LineNumAdded
 			}
+			if line.Moved {
+				lineNumColor = r.theme.LineNumMoved
+			}
 			lineNumStr = lipgloss.NewStyle().
 				Foreground(lineNumColor).
 				Width(4).
@@ -333,6 +510,9 @@ func (r *Renderer) renderHunkUnified(hunk parser.Hunk, lexer chroma.Lexer) {
 			default:
 				lineStyle = lipgloss.NewStyle()
 			}
+			if line.Moved {
+				lineStyle = r.theme.MovedLineStyle
+			}
 		} else {
 			lineStyle = lipgloss.NewStyle()
 		}
@@ -345,17 +525,38 @@ func (r *Renderer) renderHunkUnified(hunk parser.Hunk, lexer chroma.Lexer) {
 			width = textWidth
 		}
 		rendered := lineStyle.Copy().Width(width).Render(fullLine)
-		rendered = r.applyLineBackground(rendered, line.Type, useAltStyle)
+		rendered = r.applyLineBackground(rendered, line, useAltStyle)
 
-		fmt.Println(rendered)
+		if _, err := fmt.Fprintln(w, rendered); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
+// buildLineContent highlights a line's content, first cutting it to the maximum line length
 func (r *Renderer) buildLineContent(line parser.Line, lexer chroma.Lexer, counterpart string) string {
+	content, overflow := r.truncateLongLine(line.Content)
+	if overflow == 0 {
+		return r.highlightLineContent(line, lexer, counterpart)
+	}
+	line.Content = content
+	counterpart, _ = r.truncateLongLine(counterpart)
+	return r.highlightLineContent(line, lexer, counterpart) + r.overflowMarker(overflow)
+}
+
+// highlightLineContent applies syntax and inline change highlighting to a line's content
+func (r *Renderer) highlightLineContent(line parser.Line, lexer chroma.Lexer, counterpart string) string {
 	if !r.useColor {
 		return line.Content
 	}
 
+	// A moved line is unchanged text in a new place, so inline changes don't apply
+	if line.Moved {
+		counterpart = ""
+	}
+
 	segments := splitInlineSegments(line.Content, counterpart)
 	if len(segments) == 0 {
 		if lexer != nil {
@@ -392,6 +593,12 @@ func (r *Renderer) buildLineContent(line parser.Line, lexer chroma.Lexer, counte
 
 // formatLine formats a single line with line number and content
 func (r *Renderer) formatLine(line parser.Line, width int, lexer chroma.Lexer, isLeft bool, useAltStyle bool, counterpart string) string {
+	return r.formatLineRows(line, width, lexer, isLeft, useAltStyle, counterpart, false)[0]
+}
+
+// formatLineRows formats a line as one row, truncated to width, or when wrap is set as
+// several rows with the overflow continued under a blank line number
+func (r *Renderer) formatLineRows(line parser.Line, width int, lexer chroma.Lexer, isLeft bool, useAltStyle bool, counterpart string, wrap bool) []string {
 	// Get line number
 	lineNum := line.OldLineNum
 	if !isLeft {
@@ -404,6 +611,7 @@ func (r *Renderer) formatLine(line parser.Line, width int, lexer chroma.Lexer, i
 	} else {
 		lineNumStr = "    "
 	}
+	continuationNum := "    "
 
 	// Apply line number style
 	if r.useColor {
@@ -414,6 +622,9 @@ func (r *Renderer) formatLine(line parser.Line, width int, lexer chroma.Lexer, i
 		case parser.LineAdded:
 			lineNumColor = r.theme.LineNumAdded
 		}
+		if line.Moved {
+			lineNumColor = r.theme.LineNumMoved
+		}
 		lineNumStr = lipgloss.NewStyle().
 			Foreground(lineNumColor).
 			Width(4).
@@ -421,12 +632,16 @@ func (r *Renderer) formatLine(line parser.Line, width int, lexer chroma.Lexer, i
 			Render(lineNumStr)
 	}
 
-	// Format content with optional inline highlighting
-	content := r.buildLineContent(line, lexer, counterpart)
+	// Format content with optional inline highlighting. Tabs are expanded here, as
+	// lipgloss would when rendering, so the content is measured at its displayed width.
+	content := strings.ReplaceAll(r.buildLineContent(line, lexer, counterpart), "\t", "    ")
 
-	// Truncate or pad content to fit width
+	// Split overlong content into rows, or keep a single row to be truncated
 	contentWidth := width - 5 // 4 for line number, 1 for space
-	content = r.fitContent(content, contentWidth)
+	parts := []string{content}
+	if wrap && contentWidth > 0 && lipgloss.Width(content) > contentWidth {
+		parts = strings.Split(ansi.Hardwrap(content, contentWidth, true), "\n")
+	}
 
 	// Apply line style based on type with alternating support
 	var lineStyle lipgloss.Style
@@ -445,16 +660,27 @@ func (r *Renderer) formatLine(line parser.Line, width int, lexer chroma.Lexer, i
 		default:
 			lineStyle = lipgloss.NewStyle().Width(width)
 		}
+		if line.Moved {
+			lineStyle = r.theme.MovedLineStyle.Copy().Width(width)
+		}
 	} else {
 		lineStyle = lipgloss.NewStyle().Width(width)
 	}
 
-	// Combine line number and content
-	fullLine := lineNumStr + " " + content
+	rows := make([]string, len(parts))
+	for i, part := range parts {
+		num := lineNumStr
+		if i > 0 {
+			num = continuationNum
+		}
+
+		// Combine line number and content, truncated or padded to fit width
+		fullLine := num + " " + r.fitContent(part, contentWidth)
 
-	rendered := lineStyle.Render(fullLine)
-	rendered = r.applyLineBackground(rendered, line.Type, useAltStyle)
-	return rendered
+		rendered := lineStyle.Render(fullLine)
+		rows[i] = r.applyLineBackground(rendered, line, useAltStyle)
+	}
+	return rows
 }
 
 // formatEmptyLine creates an empty line for the split screen
@@ -513,11 +739,7 @@ func (r *Renderer) highlightCode(code string, lexer chroma.Lexer) string {
 		return code
 	}
 
-	// Use a dark style
-	style := styles.Get("monokai")
-	if style == nil {
-		style = styles.Fallback
-	}
+	style := r.syntaxStyle(lexer)
 
 	var buf strings.Builder
 	err = formatter.Format(&buf, style, iterator)
@@ -561,8 +783,11 @@ func stripAnsi(s string) string {
 	return result.String()
 }
 
-func (r *Renderer) backgroundForLineType(lineType parser.LineType, useAlt bool) lipgloss.Color {
-	switch lineType {
+func (r *Renderer) backgroundForLine(line parser.Line, useAlt bool) lipgloss.Color {
+	if line.Moved {
+		return r.theme.MovedBg
+	}
+	switch line.Type {
 	case parser.LineDeleted:
 		return r.theme.DeletedBg
 	case parser.LineAdded:
@@ -577,11 +802,11 @@ func (r *Renderer) backgroundForLineType(lineType parser.LineType, useAlt bool)
 	}
 }
 
-func (r *Renderer) applyLineBackground(s string, lineType parser.LineType, useAlt bool) string {
+func (r *Renderer) applyLineBackground(s string, line parser.Line, useAlt bool) string {
 	if !r.useColor || !r.theme.UseLineBackground {
 		return s
 	}
-	color := r.backgroundForLineType(lineType, useAlt)
+	color := r.backgroundForLine(line, useAlt)
 	if color == "" {
 		return s
 	}
@@ -619,10 +844,39 @@ func applyPersistentBackground(s string, color lipgloss.Color) string {
 	return builder.String()
 }
 
+// hunkGap returns the number of old-side lines hidden between two consecutive hunks.
+// Overlapping or adjacent hunks yield 0 rather than a negative count.
+func hunkGap(prev, current parser.Hunk) int {
+	// Previous hunk ends at OldStart + OldLines - 1; the current one starts at OldStart
+	prevEnd := prev.OldStart + prev.OldLines - 1
+	skipped := current.OldStart - prevEnd - 1
+	if skipped < 0 {
+		return 0
+	}
+	return skipped
+}
+
+// foldedLineCount returns the number of unchanged lines of the file that fall outside its
+// hunks: before the first hunk and between hunks. Lines after the last hunk are not
+// counted, since the diff doesn't tell how long the file is.
+func foldedLineCount(file parser.FileDiff) int {
+	if len(file.Hunks) == 0 {
+		return 0
+	}
+	folded := 0
+	if first := file.Hunks[0]; first.OldStart > 1 {
+		folded = first.OldStart - 1
+	}
+	for i := 1; i < len(file.Hunks); i++ {
+		folded += hunkGap(file.Hunks[i-1], file.Hunks[i])
+	}
+	return folded
+}
+
 func (r *Renderer) renderSkipSeparator(width int, linesSkipped int) string {
-	separatorText := "⋯"
+	separatorText := r.skipMarker
 	if linesSkipped > 0 {
-		separatorText = fmt.Sprintf("⋯ (%d lines skipped) ⋯", linesSkipped)
+		separatorText = fmt.Sprintf("%s (%d lines skipped) %s", r.skipMarker, linesSkipped, r.skipMarker)
 	}
 
 	style := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
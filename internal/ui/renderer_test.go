@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"bytes"
+	"flag"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenVariants are the renderer configurations each fixture is rendered with
+var goldenVariants = []struct {
+	name    string
+	opts    RendererOptions
+	profile termenv.Profile
+}{
+	{"nocolor-split", RendererOptions{Width: 100}, termenv.Ascii},
+	{"nocolor-unified", RendererOptions{Width: 100, Unified: true}, termenv.Ascii},
+	{"patch-split", RendererOptions{Width: 100, UseColor: true, DiffStyle: "patch"}, termenv.TrueColor},
+}
+
+func TestRenderGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "golden", "*.diff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures in testdata/golden")
+	}
+	defer lipgloss.SetColorProfile(lipgloss.ColorProfile())
+
+	for _, fixture := range fixtures {
+		input, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := parser.ParseDiff(string(input))
+		if err != nil {
+			t.Fatalf("%s: %v", fixture, err)
+		}
+
+		for _, variant := range goldenVariants {
+			name := strings.TrimSuffix(filepath.Base(fixture), ".diff") + "." + variant.name
+			t.Run(name, func(t *testing.T) {
+				lipgloss.SetColorProfile(variant.profile)
+				var out bytes.Buffer
+				if err := NewRenderer(variant.opts).RenderTo(&out, files); err != nil {
+					t.Fatal(err)
+				}
+
+				golden := filepath.Join("testdata", "golden", name+".golden")
+				if *update {
+					if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("%v (run go test ./internal/ui -update to create it)", err)
+				}
+				if !bytes.Equal(out.Bytes(), want) {
+					t.Errorf("output differs from %s (run go test ./internal/ui -update if the change is intended)\ngot:\n%s\nwant:\n%s", golden, out.String(), want)
+				}
+			})
+		}
+	}
+}
+
+// TestHunkGap checks the skipped-line counts shown between hunks, including back-to-back
+// and overlapping hunks, which show a bare separator rather than a count
+func TestHunkGap(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, hunk parser.Hunk
+		want       int
+	}{
+		{"gap", parser.Hunk{OldStart: 1, OldLines: 3}, parser.Hunk{OldStart: 10, OldLines: 2}, 6},
+		{"one line gap", parser.Hunk{OldStart: 1, OldLines: 3}, parser.Hunk{OldStart: 5, OldLines: 2}, 1},
+		{"adjacent", parser.Hunk{OldStart: 1, OldLines: 3}, parser.Hunk{OldStart: 4, OldLines: 3}, 0},
+		{"overlapping", parser.Hunk{OldStart: 4, OldLines: 3}, parser.Hunk{OldStart: 6, OldLines: 2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hunkGap(tt.prev, tt.hunk); got != tt.want {
+				t.Errorf("hunkGap = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	r := NewRenderer(RendererOptions{})
+	if got := r.renderSkipSeparator(0, 0); strings.Contains(got, "skipped") {
+		t.Errorf("separator for adjacent hunks = %q, want no count", got)
+	}
+	if got := r.renderSkipSeparator(0, 4); !strings.Contains(got, "(4 lines skipped)") {
+		t.Errorf("separator for 4 skipped lines = %q", got)
+	}
+}
+
+// TestComputeLinePairs checks that in a replaced block the k-th deleted line pairs with
+// the k-th added line, and that lines without a counterpart stay unpaired
+func TestComputeLinePairs(t *testing.T) {
+	del := func(s string) parser.Line { return parser.Line{Type: parser.LineDeleted, Content: s} }
+	add := func(s string) parser.Line { return parser.Line{Type: parser.LineAdded, Content: s} }
+	keep := func(s string) parser.Line { return parser.Line{Type: parser.LineUnchanged, Content: s} }
+
+	tests := []struct {
+		name  string
+		lines []parser.Line
+		want  map[int]string
+	}{
+		{
+			name:  "three line block",
+			lines: []parser.Line{keep("ctx"), del("a1"), del("b1"), del("c1"), add("a2"), add("b2"), add("c2"), keep("end")},
+			want:  map[int]string{1: "a2", 2: "b2", 3: "c2", 4: "a1", 5: "b1", 6: "c1"},
+		},
+		{
+			name:  "more deletions than additions",
+			lines: []parser.Line{del("a1"), del("b1"), del("c1"), add("a2"), add("b2")},
+			want:  map[int]string{0: "a2", 1: "b2", 3: "a1", 4: "b1"},
+		},
+		{
+			name:  "more additions than deletions",
+			lines: []parser.Line{del("a1"), add("a2"), add("b2"), add("c2")},
+			want:  map[int]string{0: "a2", 1: "a1"},
+		},
+		{
+			name:  "separate blocks",
+			lines: []parser.Line{del("a1"), add("a2"), keep("ctx"), del("b1"), del("c1"), add("b2"), add("c2")},
+			want:  map[int]string{0: "a2", 1: "a1", 3: "b2", 4: "c2", 5: "b1", 6: "c1"},
+		},
+		{
+			name:  "only additions and deletions",
+			lines: []parser.Line{add("a2"), keep("ctx"), del("b1")},
+			want:  map[int]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeLinePairs(tt.lines); !maps.Equal(got, tt.want) {
+				t.Errorf("computeLinePairs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/ai"
+)
+
+const (
+	reviewMarker      = "●"
+	reviewGutterWidth = 2
+)
+
+// reviewAnchor identifies a line within a file diff by hunk and line index
+type reviewAnchor struct {
+	hunk int
+	line int
+}
+
+// reviewCommentsForFile groups the current review comments for a file by their diff position
+func (m model) reviewCommentsForFile(path string) map[reviewAnchor][]ai.ReviewComment {
+	if len(m.aiReviewComments) == 0 {
+		return nil
+	}
+
+	comments := make(map[reviewAnchor][]ai.ReviewComment)
+	for _, comment := range m.aiReviewComments {
+		if comment.File != path {
+			continue
+		}
+		anchor := reviewAnchor{hunk: comment.HunkIndex, line: comment.LineIndex}
+		comments[anchor] = append(comments[anchor], comment)
+	}
+	return comments
+}
+
+// reviewSeverityStyle returns the marker style for a comment severity
+func reviewSeverityStyle(severity string) lipgloss.Style {
+	switch severity {
+	case "error":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#f85149")).Bold(true)
+	case "warning":
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#d29922")).Bold(true)
+	default:
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("#58a6ff")).Bold(true)
+	}
+}
+
+// renderReviewGutter renders the gutter cell for a diff row, marking rows that have comments
+func renderReviewGutter(comments []ai.ReviewComment) string {
+	if len(comments) == 0 {
+		return strings.Repeat(" ", reviewGutterWidth)
+	}
+
+	// Use the most severe comment for the marker color
+	severity := comments[0].Severity
+	for _, comment := range comments {
+		if comment.Severity == "error" || (comment.Severity == "warning" && severity != "error") {
+			severity = comment.Severity
+		}
+	}
+	return reviewSeverityStyle(severity).Render(reviewMarker) + " "
+}
+
+// renderReviewCommentLines renders expanded review comments to display beneath a diff row
+func renderReviewCommentLines(comments []ai.ReviewComment, width int) []string {
+	textWidth := width - 8
+	if textWidth < 20 {
+		textWidth = 20
+	}
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#f0f6fc")).
+		Background(lipgloss.Color("#21262d")).
+		Width(textWidth)
+
+	var lines []string
+	for _, comment := range comments {
+		severity := comment.Severity
+		if severity == "" {
+			severity = "info"
+		}
+		label := reviewSeverityStyle(comment.Severity).Render("[" + severity + "]")
+		for _, wrapped := range strings.Split(textStyle.Render(label+" "+comment.Comment), "\n") {
+			lines = append(lines, "    ↳ "+wrapped)
+		}
+	}
+	return lines
+}
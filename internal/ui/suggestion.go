@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danielss-dev/critica/internal/ai"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// githubSuggestion formats an improvement as a GitHub review comment with a ```suggestion
+// block seeded with the current content of the line it refers to. Improvements without a
+// location, or whose line isn't in the diff, are returned as plain text.
+func githubSuggestion(files []parser.FileDiff, improvement string) string {
+	file, lineNum, text, ok := ai.ImprovementLocation(improvement)
+	if !ok {
+		return improvement
+	}
+	content, ok := newLineContent(files, file, lineNum)
+	if !ok {
+		return improvement
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "`%s:%d`\n\n", file, lineNum)
+	b.WriteString(text)
+	b.WriteString("\n\n```suggestion\n")
+	b.WriteString(content)
+	b.WriteString("\n```\n")
+	return b.String()
+}
+
+// newLineContent returns the content of a line in the new version of a file, if the diff includes it
+func newLineContent(files []parser.FileDiff, path string, lineNum int) (string, bool) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "./"), "b/")
+	for _, file := range files {
+		if file.NewPath != path {
+			continue
+		}
+		if hunkIdx, lineIdx, ok := findNewLine(file, lineNum); ok {
+			return file.Hunks[hunkIdx].Lines[lineIdx].Content, true
+		}
+	}
+	return "", false
+}
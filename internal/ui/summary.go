@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// formatSummaryFooter returns the closing "N files changed, +A insertions, -D deletions"
+// line for the rendered files
+func (r *Renderer) formatSummaryFooter(files []parser.FileDiff) string {
+	added, deleted := 0, 0
+	for _, file := range files {
+		a, d := fileLineCounts(file)
+		added += a
+		deleted += d
+	}
+
+	insertions := fmt.Sprintf("+%d %s", added, plural(added, "insertion", "insertions"))
+	deletions := fmt.Sprintf("-%d %s", deleted, plural(deleted, "deletion", "deletions"))
+	if r.useColor {
+		insertions = lipgloss.NewStyle().Foreground(r.theme.AddedFg).Render(insertions)
+		deletions = lipgloss.NewStyle().Foreground(r.theme.DeletedFg).Render(deletions)
+	}
+
+	return fmt.Sprintf("%d %s changed, %s, %s", len(files), plural(len(files), "file", "files"), insertions, deletions)
+}
+
+// plural picks the singular or plural form of a word for n
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// defaultSyntaxStyle is the chroma style used for languages without an override
+const defaultSyntaxStyle = "monokai"
+
+// syntaxStyle returns the chroma style for code highlighted with lexer: the override
+// configured for its language name, one of its aliases or one of its file extensions,
+// or defaultSyntaxStyle
+func (r *Renderer) syntaxStyle(lexer chroma.Lexer) *chroma.Style {
+	name := defaultSyntaxStyle
+	if override, ok := r.syntaxStyleOverride(lexer); ok {
+		name = override
+	}
+	style := styles.Get(name)
+	if style == nil {
+		style = styles.Fallback
+	}
+	return style
+}
+
+// syntaxStyleOverride looks up the configured style for the lexer's language
+func (r *Renderer) syntaxStyleOverride(lexer chroma.Lexer) (string, bool) {
+	if len(r.syntaxStyles) == 0 || lexer == nil {
+		return "", false
+	}
+	config := lexer.Config()
+	if config == nil {
+		return "", false
+	}
+
+	keys := append([]string{config.Name}, config.Aliases...)
+	for _, pattern := range config.Filenames {
+		keys = append(keys, strings.TrimPrefix(pattern, "*."))
+	}
+	for _, key := range keys {
+		if style, ok := r.syntaxStyles[strings.ToLower(key)]; ok {
+			return style, true
+		}
+	}
+	return "", false
+}
@@ -15,6 +15,7 @@ type Theme struct {
 	UnchangedBgStripe lipgloss.Color // Alternating row color
 	InlineDeletedBg   lipgloss.Color
 	InlineAddedBg     lipgloss.Color
+	MovedBg           lipgloss.Color // Lines of a block moved within the file
 
 	// Foreground colors
 	DeletedFg       lipgloss.Color
@@ -22,11 +23,13 @@ type Theme struct {
 	UnchangedFg     lipgloss.Color
 	InlineDeletedFg lipgloss.Color
 	InlineAddedFg   lipgloss.Color
+	MovedFg         lipgloss.Color
 
 	// Line number colors
 	LineNumDeleted   lipgloss.Color
 	LineNumAdded     lipgloss.Color
 	LineNumUnchanged lipgloss.Color
+	LineNumMoved     lipgloss.Color
 
 	// Header colors
 	FileHeaderBg lipgloss.Color
@@ -42,6 +45,7 @@ type Theme struct {
 	UnchangedLineStyleAlt lipgloss.Style // Alternating style
 	InlineDeletedStyle    lipgloss.Style
 	InlineAddedStyle      lipgloss.Style
+	MovedLineStyle        lipgloss.Style
 	LineNumStyle          lipgloss.Style
 	FileHeaderStyle       lipgloss.Style
 	SeparatorStyle        lipgloss.Style
@@ -78,14 +82,17 @@ func newFilledTheme(opts ThemeOptions) *Theme {
 		UnchangedBgStripe: lipgloss.Color("#1c1f29"),
 		InlineDeletedBg:   lipgloss.Color("#6f3246"),
 		InlineAddedBg:     lipgloss.Color("#255961"),
+		MovedBg:           lipgloss.Color("#342c52"),
 		DeletedFg:         deletedFg,
 		AddedFg:           addedFg,
 		UnchangedFg:       lipgloss.Color("#d7dbe4"),
 		InlineDeletedFg:   lipgloss.Color("#ffeaf2"),
 		InlineAddedFg:     lipgloss.Color("#e4fff4"),
+		MovedFg:           lipgloss.Color("#c9b8ff"),
 		LineNumDeleted:    dimOrOverride(lipgloss.Color("#b86c7a"), opts.DeletedTextColor),
 		LineNumAdded:      dimOrOverride(lipgloss.Color("#68bda1"), opts.AddedTextColor),
 		LineNumUnchanged:  lipgloss.Color("#6f7688"),
+		LineNumMoved:      lipgloss.Color("#8f80c4"),
 		FileHeaderBg:      lipgloss.Color("#161922"),
 		FileHeaderFg:      lipgloss.Color("#edf0f7"),
 		BorderColor:       lipgloss.Color("#2f3541"),
@@ -116,6 +123,10 @@ func newFilledTheme(opts ThemeOptions) *Theme {
 		Background(t.InlineAddedBg).
 		Foreground(t.InlineAddedFg)
 
+	t.MovedLineStyle = lipgloss.NewStyle().
+		Background(t.MovedBg).
+		Foreground(t.MovedFg)
+
 	t.LineNumStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#565d70")).
 		Width(5).
@@ -141,17 +152,20 @@ func newDefaultTheme(opts ThemeOptions) *Theme {
 		DeletedBg:         lipgloss.Color("#3a2020"),
 		AddedBg:           lipgloss.Color("#203a20"),
 		UnchangedBg:       lipgloss.Color(""),
-		UnchangedBgStripe: lipgloss.Color(""),
+		UnchangedBgStripe: lipgloss.Color("#1c1c1c"), // Subtle stripe so alternating rows are visible
 		InlineDeletedBg:   lipgloss.Color("#6b2c2c"),
 		InlineAddedBg:     lipgloss.Color("#2c6b2c"),
+		MovedBg:           lipgloss.Color("#2c2440"),
 		DeletedFg:         deletedFg,
 		AddedFg:           addedFg,
 		UnchangedFg:       lipgloss.Color("#a0a0a0"),
 		InlineDeletedFg:   lipgloss.Color("#ffeeee"),
 		InlineAddedFg:     lipgloss.Color("#eeffee"),
+		MovedFg:           lipgloss.Color("#a991d4"),
 		LineNumDeleted:    dimOrOverride(lipgloss.Color("#7a5f5f"), opts.DeletedTextColor),
 		LineNumAdded:      dimOrOverride(lipgloss.Color("#5f7a5f"), opts.AddedTextColor),
 		LineNumUnchanged:  lipgloss.Color("#4a4a4a"),
+		LineNumMoved:      lipgloss.Color("#6d5f8a"),
 		FileHeaderBg:      lipgloss.Color("#2a2a2a"),
 		FileHeaderFg:      lipgloss.Color("#d0d0d0"),
 		BorderColor:       lipgloss.Color("#333333"),
@@ -172,6 +186,7 @@ func newDefaultTheme(opts ThemeOptions) *Theme {
 		Foreground(t.UnchangedFg)
 
 	t.UnchangedLineStyleAlt = lipgloss.NewStyle().
+		Background(t.UnchangedBgStripe).
 		Foreground(t.UnchangedFg)
 
 	t.InlineDeletedStyle = lipgloss.NewStyle().
@@ -184,6 +199,11 @@ func newDefaultTheme(opts ThemeOptions) *Theme {
 		Foreground(t.InlineAddedFg).
 		Bold(true)
 
+	t.MovedLineStyle = lipgloss.NewStyle().
+		Background(t.MovedBg).
+		Foreground(t.MovedFg).
+		Bold(true)
+
 	t.LineNumStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#888888")).
 		Width(5).
@@ -212,14 +232,17 @@ func newPatchTheme(opts ThemeOptions) *Theme {
 		UnchangedBgStripe: lipgloss.Color(""),
 		InlineDeletedBg:   lipgloss.Color("#ff6363"),
 		InlineAddedBg:     lipgloss.Color("#34d399"),
+		MovedBg:           lipgloss.Color(""),
 		DeletedFg:         deletedFg,
 		AddedFg:           addedFg,
 		UnchangedFg:       lipgloss.Color("#c0c0c0"),
 		InlineDeletedFg:   lipgloss.Color("#1f2937"),
 		InlineAddedFg:     lipgloss.Color("#1f2937"),
+		MovedFg:           lipgloss.Color("#c792ea"),
 		LineNumDeleted:    deletedFg,
 		LineNumAdded:      addedFg,
 		LineNumUnchanged:  lipgloss.Color("#6b7280"),
+		LineNumMoved:      lipgloss.Color("#c792ea"),
 		FileHeaderBg:      lipgloss.Color("#2a2a2a"),
 		FileHeaderFg:      lipgloss.Color("#d0d0d0"),
 		BorderColor:       lipgloss.Color("#333333"),
@@ -240,6 +263,10 @@ func newPatchTheme(opts ThemeOptions) *Theme {
 	t.UnchangedLineStyleAlt = lipgloss.NewStyle().
 		Foreground(t.UnchangedFg)
 
+	t.MovedLineStyle = lipgloss.NewStyle().
+		Foreground(t.MovedFg).
+		Bold(true)
+
 	t.InlineDeletedStyle = lipgloss.NewStyle().
 		Background(t.InlineDeletedBg).
 		Foreground(t.InlineDeletedFg).
@@ -291,6 +318,7 @@ func NoColorTheme() *Theme {
 	t.UnchangedLineStyleAlt = lipgloss.NewStyle()
 	t.InlineDeletedStyle = lipgloss.NewStyle()
 	t.InlineAddedStyle = lipgloss.NewStyle()
+	t.MovedLineStyle = lipgloss.NewStyle()
 	t.LineNumStyle = lipgloss.NewStyle().Width(5).Align(lipgloss.Right)
 	t.FileHeaderStyle = lipgloss.NewStyle().Bold(true).Padding(0, 1)
 	t.SeparatorStyle = lipgloss.NewStyle()
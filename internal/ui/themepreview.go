@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// DiffStyles lists the diff styles accepted by NewTheme, in the order they are previewed
+var DiffStyles = []string{"default", "patch", "filled"}
+
+// diffStyleName returns the style a theme was built with, as listed in DiffStyles;
+// NewTheme falls back to "default" for anything unknown
+func diffStyleName(style string) string {
+	style = strings.ToLower(strings.TrimSpace(style))
+	for _, name := range DiffStyles {
+		if name == style {
+			return name
+		}
+	}
+	return DiffStyles[0]
+}
+
+// cycleDiffStyle switches the renderer to the next style in DiffStyles
+func (r *Renderer) cycleDiffStyle() {
+	current := diffStyleName(r.themeOptions.DiffStyle)
+	for i, name := range DiffStyles {
+		if name == current {
+			r.themeOptions.DiffStyle = DiffStyles[(i+1)%len(DiffStyles)]
+			break
+		}
+	}
+	if r.useColor {
+		r.theme = NewTheme(r.themeOptions)
+	}
+}
+
+// themePreviewDiff is a small diff with unchanged, changed, added and deleted lines
+const themePreviewDiff = `diff --git a/greet.go b/greet.go
+index 3b18e51..a1c2f4d 100644
+--- a/greet.go
++++ b/greet.go
+@@ -1,9 +1,11 @@
+ package main
+ 
+ import "fmt"
+ 
+-func greet(name string) {
+-	fmt.Println("Hello " + name)
++func greet(name string, excited bool) {
++	fmt.Printf("Hello, %s%s\n", name, punctuation(excited))
+ }
+ 
+-// TODO: remove
++func main() {
++	greet("critica", true)
++}
+`
+
+// RenderThemePreview renders the same sample diff once per diff style, each under a
+// label, so the styles can be compared. DiffStyle in opts is ignored; the color
+// overrides and layout options apply to every style.
+func RenderThemePreview(w io.Writer, opts RendererOptions) error {
+	files, err := parser.ParseDiff(themePreviewDiff)
+	if err != nil {
+		return fmt.Errorf("failed to parse preview diff: %w", err)
+	}
+
+	for i, style := range DiffStyles {
+		label := fmt.Sprintf("diff_style: %s", style)
+		if opts.UseColor {
+			label = lipgloss.NewStyle().Bold(true).Render(label)
+		}
+		if i > 0 {
+			label = "\n" + label
+		}
+		if _, err := fmt.Fprintln(w, label); err != nil {
+			return err
+		}
+
+		styleOpts := opts
+		styleOpts.DiffStyle = style
+		if err := NewRenderer(styleOpts).RenderTo(w, files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
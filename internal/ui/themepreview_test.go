@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/danielss-dev/critica/internal/parser"
+)
+
+// TestThemePreviewDiffCounts checks that the preview diff's hunk header counts match its
+// body, so the parser doesn't cut the sample short
+func TestThemePreviewDiffCounts(t *testing.T) {
+	files, err := parser.ParseDiff(themePreviewDiff)
+	if err != nil {
+		t.Fatalf("ParseDiff: %v", err)
+	}
+	if len(files) != 1 || len(files[0].Hunks) != 1 {
+		t.Fatalf("parsed %d files, want one file with one hunk", len(files))
+	}
+
+	hunk := files[0].Hunks[0]
+	oldLines, newLines := 0, 0
+	for _, line := range hunk.Lines {
+		if line.Type != parser.LineAdded {
+			oldLines++
+		}
+		if line.Type != parser.LineDeleted {
+			newLines++
+		}
+	}
+	if oldLines != hunk.OldLines || newLines != hunk.NewLines {
+		t.Errorf("hunk has %d old and %d new lines, header says %d and %d", oldLines, newLines, hunk.OldLines, hunk.NewLines)
+	}
+
+	// Every line after the hunk header belongs to the hunk
+	body := themePreviewDiff[strings.Index(themePreviewDiff, "@@"):]
+	if want := strings.Count(body, "\n") - 1; len(hunk.Lines) != want {
+		t.Errorf("parsed %d hunk lines, the body has %d", len(hunk.Lines), want)
+	}
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// listItemPrefix matches bullet and numbered list markers, including their indentation
+var listItemPrefix = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+`)
+
+// wrapText wraps text to width at word boundaries so viewport line counts match what
+// the terminal displays. Continuation lines of list items are indented under the item text.
+func wrapText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = wrapLine(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapLine wraps a single line, hanging continuation lines under the list marker
+func wrapLine(line string, width int) string {
+	if lipgloss.Width(line) <= width {
+		return line
+	}
+
+	prefix := listItemPrefix.FindString(line)
+	if prefix == "" {
+		prefix = line[:len(line)-len(strings.TrimLeft(line, " "))]
+	}
+	indent := lipgloss.Width(prefix)
+	if indent >= width/2 {
+		prefix, indent = "", 0
+	}
+
+	wrapped := strings.Split(ansi.Wrap(line[len(prefix):], width-indent, ""), "\n")
+	for i, part := range wrapped {
+		if i == 0 {
+			wrapped[i] = prefix + part
+			continue
+		}
+		wrapped[i] = strings.Repeat(" ", indent) + strings.TrimLeft(part, " ")
+	}
+	return strings.Join(wrapped, "\n")
+}
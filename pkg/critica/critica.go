@@ -0,0 +1,119 @@
+// Package critica exposes critica's diff parsing and rendering for use in other tools.
+package critica
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/danielss-dev/critica/internal/parser"
+	"github.com/danielss-dev/critica/internal/ui"
+)
+
+// Diff style names accepted by Options.DiffStyle
+const (
+	DiffStyleDefault = "default"
+	DiffStylePatch   = "patch"
+	DiffStyleFilled  = "filled"
+)
+
+// Options describes how a diff is rendered.
+type Options struct {
+	// UseColor enables colors and syntax highlighting
+	UseColor bool
+	// ForceColor keeps colors when the output is not a terminal, e.g. in CI logs
+	ForceColor bool
+	// Unified renders a unified diff instead of the split-screen view
+	Unified bool
+	// DiffStyle selects the color theme (default, patch or filled)
+	DiffStyle string
+	// AddedTextColor and DeletedTextColor override the theme's text colors (#rrggbb)
+	AddedTextColor   string
+	DeletedTextColor string
+	// Width is the output width in columns; the terminal width is used when zero. Set it
+	// (with UseColor false) for output that is the same on every machine.
+	Width int
+	// SplitMinWidth renders the split view unified when the output width is below it, so narrow
+	// output stays readable; 0 always renders split
+	SplitMinWidth int
+	// MovedMinLines highlights blocks of at least this many non-blank lines that were moved
+	// within a file in a distinct color instead of as a deletion and an addition; 0 disables
+	MovedMinLines int
+	// Wrap continues long lines onto extra rows in the split view instead of truncating them
+	Wrap bool
+	// Compact drops the blank lines around file headers and between hunks
+	Compact bool
+	// MaxLineLength cuts lines longer than this many characters, ending them with a
+	// "… (N more chars)" note, so minified files render quickly; 0 shows lines in full
+	MaxLineLength int
+	// HighlightLimit renders files with more diff lines than this without syntax
+	// highlighting, noting it in their header; 0 always highlights
+	HighlightLimit int
+	// SyntaxStyles maps lowercase language names (e.g. "go", "bash") or extensions to the
+	// chroma style their code is highlighted with instead of monokai
+	SyntaxStyles map[string]string
+	// SummaryFooter ends the output with a "N files changed, +A insertions, -D deletions" line
+	SummaryFooter bool
+	// ColumnSeparator replaces the "│" between split columns and must be one column wide;
+	// SkipMarker replaces the "⋯" marking folded lines between hunks. Empty keeps the defaults.
+	ColumnSeparator string
+	SkipMarker      string
+	// PathStyle shows file paths relative to RepoRoot ("repo", the default), to the current
+	// directory ("relative") or as absolute paths ("absolute"). The diff's paths are
+	// repo-relative, so the other styles need RepoRoot, the repository's top-level directory.
+	PathStyle string
+	RepoRoot  string
+}
+
+// RenderDiffString parses git diff output and renders it to a string.
+func RenderDiffString(diff string, opts Options) (string, error) {
+	var b strings.Builder
+	if err := RenderDiff(&b, diff, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderDiff parses git diff output and writes the rendered diff to w.
+func RenderDiff(w io.Writer, diff string, opts Options) error {
+	if diff == "" {
+		return nil
+	}
+	return RenderDiffReader(w, strings.NewReader(diff), opts)
+}
+
+// RenderDiffReader parses git diff output read from r and writes the rendered diff to w.
+// The diff is parsed as it is read, so large diffs never need to be loaded as a string.
+func RenderDiffReader(w io.Writer, r io.Reader, opts Options) error {
+	files, err := parser.ParseReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to parse diff: %w", err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	renderer := ui.NewRenderer(ui.RendererOptions{
+		UseColor:         opts.UseColor,
+		ForceColor:       opts.ForceColor,
+		Unified:          opts.Unified,
+		DiffStyle:        opts.DiffStyle,
+		AddedTextColor:   opts.AddedTextColor,
+		DeletedTextColor: opts.DeletedTextColor,
+		Width:            opts.Width,
+		SplitMinWidth:    opts.SplitMinWidth,
+		MovedMinLines:    opts.MovedMinLines,
+		Wrap:             opts.Wrap,
+		Compact:          opts.Compact,
+		MaxLineLength:    opts.MaxLineLength,
+		HighlightLimit:   opts.HighlightLimit,
+		SyntaxStyles:     opts.SyntaxStyles,
+		SummaryFooter:    opts.SummaryFooter,
+		ColumnSeparator:  opts.ColumnSeparator,
+		SkipMarker:       opts.SkipMarker,
+		PathStyle:        opts.PathStyle,
+		RepoRoot:         opts.RepoRoot,
+	})
+
+	return renderer.RenderTo(w, files)
+}